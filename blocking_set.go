@@ -0,0 +1,177 @@
+package set
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrClosed is returned by BlockingSet.PopWait once the set is closed and empty
+var ErrClosed = errors.New("set: blocking set is closed")
+
+// BlockingSet is a thread-safe set whose consumers can wait for elements,
+// which makes it usable as a deduplicating work queue: an element added while
+// it is already pending is only handed out once.
+type BlockingSet[T comparable] struct {
+	m      map[T]struct{}
+	l      sync.Mutex
+	ready  chan struct{} // closed to wake waiters, created by the first waiter
+	closed bool
+}
+
+// NewBlockingSet returns a new blocking set
+func NewBlockingSet[T comparable]() *BlockingSet[T] {
+	return &BlockingSet[T]{
+		m: make(map[T]struct{}),
+	}
+}
+
+// Add adds an element to the set and wakes any waiting consumers
+// It returns false if the element is already pending or the set is closed
+func (s *BlockingSet[T]) Add(e T) bool {
+	s.l.Lock()
+	defer s.l.Unlock()
+	if s.closed {
+		return false
+	}
+	if _, ok := s.m[e]; ok {
+		return false
+	}
+	s.m[e] = struct{}{}
+	s.wake()
+	return true
+}
+
+// Contains returns true if the element is pending
+func (s *BlockingSet[T]) Contains(e T) bool {
+	s.l.Lock()
+	defer s.l.Unlock()
+	_, ok := s.m[e]
+	return ok
+}
+
+// Remove removes a pending element from the set
+func (s *BlockingSet[T]) Remove(e T) {
+	s.l.Lock()
+	defer s.l.Unlock()
+	delete(s.m, e)
+}
+
+// Len returns the number of pending elements
+func (s *BlockingSet[T]) Len() int {
+	s.l.Lock()
+	defer s.l.Unlock()
+	return len(s.m)
+}
+
+// IsEmpty returns true if no elements are pending
+func (s *BlockingSet[T]) IsEmpty() bool {
+	return s.Len() == 0
+}
+
+// TryPop removes and returns an arbitrary element and true, or the zero value of T and false if the set is empty
+// It never blocks
+func (s *BlockingSet[T]) TryPop() (T, bool) {
+	s.l.Lock()
+	defer s.l.Unlock()
+	return s.pop()
+}
+
+// PopWait removes and returns an arbitrary element, blocking until one is available.
+// It returns ctx.Err() if the context is done first, and ErrClosed once the set is
+// closed and every pending element has been popped.
+func (s *BlockingSet[T]) PopWait(ctx context.Context) (T, error) {
+	for {
+		s.l.Lock()
+		if e, ok := s.pop(); ok {
+			s.l.Unlock()
+			return e, nil
+		}
+		if s.closed {
+			s.l.Unlock()
+			var zero T
+			return zero, ErrClosed
+		}
+		if s.ready == nil {
+			s.ready = make(chan struct{})
+		}
+		ready := s.ready
+		s.l.Unlock()
+		select {
+		case <-ready:
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// Close stops the set from accepting new elements and wakes every waiting consumer
+// Elements that are already pending can still be popped
+func (s *BlockingSet[T]) Close() {
+	s.l.Lock()
+	defer s.l.Unlock()
+	s.closed = true
+	s.wake()
+}
+
+// Chan returns a channel that receives the elements of the set as they become available.
+// The channel is closed when ctx is done, or when the set is closed and drained.
+// An element popped for a consumer that never received it is put back in the set.
+func (s *BlockingSet[T]) Chan(ctx context.Context) <-chan T {
+	ch := make(chan T)
+	go func() {
+		defer close(ch)
+		for {
+			e, err := s.PopWait(ctx)
+			if err != nil {
+				return
+			}
+			select {
+			case ch <- e:
+			case <-ctx.Done():
+				s.requeue(e)
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// String returns a string representation of the pending elements
+func (s *BlockingSet[T]) String() string {
+	s.l.Lock()
+	defer s.l.Unlock()
+	slice := make([]T, 0, len(s.m))
+	for k := range s.m {
+		slice = append(slice, k)
+	}
+	return fmt.Sprintf("%v", slice)
+}
+
+// pop removes and returns an arbitrary element, the lock must be held
+func (s *BlockingSet[T]) pop() (T, bool) {
+	var zero T
+	for k := range s.m {
+		delete(s.m, k)
+		return k, true
+	}
+	return zero, false
+}
+
+// wake wakes every waiting consumer, the lock must be held
+func (s *BlockingSet[T]) wake() {
+	if s.ready != nil {
+		close(s.ready)
+		s.ready = nil
+	}
+}
+
+// requeue puts a popped element back in the set, even if it has been closed since
+func (s *BlockingSet[T]) requeue(e T) {
+	s.l.Lock()
+	defer s.l.Unlock()
+	s.m[e] = struct{}{}
+	s.wake()
+}
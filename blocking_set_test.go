@@ -0,0 +1,148 @@
+package set
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBlockingSet_Add(t *testing.T) {
+	s := NewBlockingSet[string]()
+	if !s.Add("a") {
+		t.Error("BlockingSet.Add() returned false for a new element")
+	}
+	if s.Add("a") {
+		t.Error("BlockingSet.Add() returned true for a pending element")
+	}
+	if !s.Contains("a") || s.Len() != 1 || s.IsEmpty() {
+		t.Errorf("Expected {a}, got %v", s)
+	}
+	s.Remove("a")
+	if !s.IsEmpty() {
+		t.Errorf("Expected empty set, got %v", s)
+	}
+}
+
+func TestBlockingSet_TryPop(t *testing.T) {
+	s := NewBlockingSet[int]()
+	if _, ok := s.TryPop(); ok {
+		t.Error("BlockingSet.TryPop() returned true for an empty set")
+	}
+	s.Add(0)
+	if v, ok := s.TryPop(); !ok || v != 0 {
+		t.Errorf("Expected (0, true), got (%d, %v)", v, ok)
+	}
+}
+
+// go test -race -run TestBlockingSet_PopWait .
+func TestBlockingSet_PopWait(t *testing.T) {
+	s := NewBlockingSet[int]()
+	got := make(chan int)
+	go func() {
+		v, err := s.PopWait(context.Background())
+		if err != nil {
+			t.Errorf("BlockingSet.PopWait() returned %v", err)
+		}
+		got <- v
+	}()
+	select {
+	case v := <-got:
+		t.Fatalf("BlockingSet.PopWait() returned %d from an empty set", v)
+	case <-time.After(20 * time.Millisecond):
+	}
+	s.Add(3)
+	if v := <-got; v != 3 {
+		t.Errorf("Expected 3, got %d", v)
+	}
+}
+
+func TestBlockingSet_PopWaitCancel(t *testing.T) {
+	s := NewBlockingSet[int]()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := s.PopWait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Expected %v, got %v", context.DeadlineExceeded, err)
+	}
+}
+
+// go test -race -run TestBlockingSet_Close .
+func TestBlockingSet_Close(t *testing.T) {
+	s := NewBlockingSet[int]()
+	var wg sync.WaitGroup
+	wg.Add(5)
+	for i := 0; i < 5; i++ {
+		go func() {
+			defer wg.Done()
+			if _, err := s.PopWait(context.Background()); err != ErrClosed {
+				t.Errorf("Expected %v, got %v", ErrClosed, err)
+			}
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	s.Close()
+	wg.Wait()
+
+	s = NewBlockingSet[int]()
+	s.Add(1)
+	s.Close()
+	if s.Add(2) {
+		t.Error("BlockingSet.Add() returned true after Close()")
+	}
+	if v, err := s.PopWait(context.Background()); err != nil || v != 1 {
+		t.Errorf("Expected pending element 1 after Close(), got (%d, %v)", v, err)
+	}
+	if _, err := s.PopWait(context.Background()); err != ErrClosed {
+		t.Errorf("Expected %v, got %v", ErrClosed, err)
+	}
+}
+
+// go test -race -run TestBlockingSet_Chan .
+func TestBlockingSet_Chan(t *testing.T) {
+	s := NewBlockingSet[int]()
+	ch := s.Chan(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(4)
+	for p := 0; p < 4; p++ {
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				s.Add(i)
+			}
+		}()
+	}
+	got := NewSet[int]()
+	done := make(chan struct{})
+	go func() {
+		for v := range ch {
+			got.Add(v)
+		}
+		close(done)
+	}()
+	wg.Wait()
+	s.Close()
+	<-done
+	if got.Len() != 50 {
+		t.Errorf("Expected 50 distinct elements, got %d", got.Len())
+	}
+}
+
+func TestBlockingSet_ChanCancel(t *testing.T) {
+	s := NewBlockingSet[int]()
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := s.Chan(ctx)
+	s.Add(1)
+	// give the consumer goroutine time to pop the element before nobody receives it
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	deadline := time.Now().Add(time.Second)
+	for !s.Contains(1) && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if !s.Contains(1) {
+		t.Error("Expected an element that was never received to be put back")
+	}
+	if _, ok := <-ch; ok {
+		t.Error("Expected the channel to be closed after the context is done")
+	}
+}
@@ -0,0 +1,445 @@
+package set
+
+import (
+	"container/heap"
+	"container/list"
+	"fmt"
+	"math/rand"
+	"sync"
+)
+
+// EvictionPolicy decides which element a full BoundedSet evicts
+type EvictionPolicy int
+
+const (
+	// LRU evicts the least recently used element
+	LRU EvictionPolicy = iota
+	// LFU evicts the least frequently used element, the least recently used of them on a tie
+	LFU
+	// FIFO evicts the element that was added first
+	FIFO
+	// Random evicts an arbitrary element
+	Random
+)
+
+// String returns the name of the eviction policy
+func (p EvictionPolicy) String() string {
+	switch p {
+	case LRU:
+		return "LRU"
+	case LFU:
+		return "LFU"
+	case FIFO:
+		return "FIFO"
+	case Random:
+		return "Random"
+	}
+	return fmt.Sprintf("EvictionPolicy(%d)", int(p))
+}
+
+// BoundedStats counts the lookups and evictions of a bounded set
+type BoundedStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+}
+
+// BoundedSet is a set that never holds more than a fixed number of elements.
+// Adding an element to a full set evicts another one, chosen by the eviction policy.
+// Add and Contains count as uses of an element for LRU and LFU.
+type BoundedSet[T comparable] struct {
+	capacity int
+	policy   EvictionPolicy
+	e        evictor[T]
+	onEvict  func(T)
+	stats    BoundedStats
+}
+
+// NewBoundedSet returns a new bounded set holding at most capacity elements
+// It panics if capacity is less than one
+func NewBoundedSet[T comparable](capacity int, policy EvictionPolicy) *BoundedSet[T] {
+	if capacity < 1 {
+		panic("set: bounded set capacity must be at least one")
+	}
+	return &BoundedSet[T]{
+		capacity: capacity,
+		policy:   policy,
+		e:        newEvictor[T](policy),
+	}
+}
+
+// OnEvict sets a function that is called with every evicted element
+func (s *BoundedSet[T]) OnEvict(fn func(T)) {
+	s.onEvict = fn
+}
+
+// Add adds an element to the set, evicting another one if the set is full
+func (s *BoundedSet[T]) Add(e T) {
+	if evicted, ok := s.add(e); ok && s.onEvict != nil {
+		s.onEvict(evicted)
+	}
+}
+
+// Contains returns true if the set contains the element and counts a hit or a miss
+func (s *BoundedSet[T]) Contains(e T) bool {
+	if s.e.access(e) {
+		s.stats.Hits++
+		return true
+	}
+	s.stats.Misses++
+	return false
+}
+
+// Remove removes an element from the set
+func (s *BoundedSet[T]) Remove(e T) {
+	s.e.remove(e)
+}
+
+// Len returns the number of elements in the set
+func (s *BoundedSet[T]) Len() int {
+	return s.e.len()
+}
+
+// Cap returns the maximum number of elements in the set
+func (s *BoundedSet[T]) Cap() int {
+	return s.capacity
+}
+
+// IsEmpty returns true if the set is empty
+func (s *BoundedSet[T]) IsEmpty() bool {
+	return s.Len() == 0
+}
+
+// Clear removes all elements from the set, the statistics are kept
+func (s *BoundedSet[T]) Clear() {
+	s.e = newEvictor[T](s.policy)
+}
+
+// ToSlice returns a slice of the elements in the set
+func (s *BoundedSet[T]) ToSlice() []T {
+	slice := make([]T, 0, s.e.len())
+	s.e.each(func(e T) {
+		slice = append(slice, e)
+	})
+	return slice
+}
+
+// Stats returns the hits, misses and evictions counted so far
+func (s *BoundedSet[T]) Stats() BoundedStats {
+	return s.stats
+}
+
+// String returns a string representation of the set
+func (s *BoundedSet[T]) String() string {
+	return fmt.Sprintf("%v", s.ToSlice())
+}
+
+// add adds an element and returns the element it evicted, if any
+func (s *BoundedSet[T]) add(e T) (T, bool) {
+	var zero T
+	if s.e.access(e) {
+		return zero, false
+	}
+	var evicted T
+	ok := false
+	if s.e.len() >= s.capacity {
+		evicted, ok = s.e.evict(), true
+		s.stats.Evictions++
+	}
+	s.e.add(e)
+	return evicted, ok
+}
+
+// ThreadSafeBoundedSet is a thread-safe bounded set
+// Like ThreadSafeSet, every method locks a mutex once for the whole operation
+type ThreadSafeBoundedSet[T comparable] struct {
+	s *BoundedSet[T]
+	l sync.Mutex
+}
+
+// NewThreadSafeBoundedSet returns a new thread-safe bounded set holding at most capacity elements
+// It panics if capacity is less than one
+func NewThreadSafeBoundedSet[T comparable](capacity int, policy EvictionPolicy) *ThreadSafeBoundedSet[T] {
+	return &ThreadSafeBoundedSet[T]{
+		s: NewBoundedSet[T](capacity, policy),
+	}
+}
+
+// OnEvict sets a function that is called with every evicted element
+// It is called without the set's lock held
+func (s *ThreadSafeBoundedSet[T]) OnEvict(fn func(T)) {
+	s.l.Lock()
+	defer s.l.Unlock()
+	s.s.OnEvict(fn)
+}
+
+// Add adds an element to the set, evicting another one if the set is full
+func (s *ThreadSafeBoundedSet[T]) Add(e T) {
+	s.l.Lock()
+	evicted, ok := s.s.add(e)
+	fn := s.s.onEvict
+	s.l.Unlock()
+	if ok && fn != nil {
+		fn(evicted)
+	}
+}
+
+// Contains returns true if the set contains the element and counts a hit or a miss
+func (s *ThreadSafeBoundedSet[T]) Contains(e T) bool {
+	s.l.Lock()
+	defer s.l.Unlock()
+	return s.s.Contains(e)
+}
+
+// Remove removes an element from the set
+func (s *ThreadSafeBoundedSet[T]) Remove(e T) {
+	s.l.Lock()
+	defer s.l.Unlock()
+	s.s.Remove(e)
+}
+
+// Len returns the number of elements in the set
+func (s *ThreadSafeBoundedSet[T]) Len() int {
+	s.l.Lock()
+	defer s.l.Unlock()
+	return s.s.Len()
+}
+
+// Cap returns the maximum number of elements in the set
+func (s *ThreadSafeBoundedSet[T]) Cap() int {
+	return s.s.Cap()
+}
+
+// IsEmpty returns true if the set is empty
+func (s *ThreadSafeBoundedSet[T]) IsEmpty() bool {
+	return s.Len() == 0
+}
+
+// Clear removes all elements from the set, the statistics are kept
+func (s *ThreadSafeBoundedSet[T]) Clear() {
+	s.l.Lock()
+	defer s.l.Unlock()
+	s.s.Clear()
+}
+
+// ToSlice returns a slice of the elements in the set
+func (s *ThreadSafeBoundedSet[T]) ToSlice() []T {
+	s.l.Lock()
+	defer s.l.Unlock()
+	return s.s.ToSlice()
+}
+
+// Stats returns the hits, misses and evictions counted so far
+func (s *ThreadSafeBoundedSet[T]) Stats() BoundedStats {
+	s.l.Lock()
+	defer s.l.Unlock()
+	return s.s.Stats()
+}
+
+// String returns a string representation of the set
+func (s *ThreadSafeBoundedSet[T]) String() string {
+	return fmt.Sprintf("%v", s.ToSlice())
+}
+
+// evictor stores the elements of a bounded set in the order its policy evicts them
+type evictor[T comparable] interface {
+	// add adds an element that is not stored
+	add(e T)
+	// access records a use of the element and returns true if it is stored
+	access(e T) bool
+	remove(e T)
+	// evict removes and returns the next element to evict, the evictor must not be empty
+	evict() T
+	len() int
+	each(fn func(T))
+}
+
+func newEvictor[T comparable](policy EvictionPolicy) evictor[T] {
+	switch policy {
+	case LFU:
+		return &lfuEvictor[T]{index: make(map[T]*lfuEntry[T])}
+	case FIFO:
+		return &listEvictor[T]{index: make(map[T]*list.Element), order: list.New()}
+	case Random:
+		return &randomEvictor[T]{index: make(map[T]int)}
+	}
+	return &listEvictor[T]{index: make(map[T]*list.Element), order: list.New(), moveOnAccess: true}
+}
+
+// listEvictor evicts the back of a list, elements are added to the front
+// It is LRU when used elements move to the front and FIFO otherwise
+type listEvictor[T comparable] struct {
+	index        map[T]*list.Element
+	order        *list.List
+	moveOnAccess bool
+}
+
+func (l *listEvictor[T]) add(e T) {
+	l.index[e] = l.order.PushFront(e)
+}
+
+func (l *listEvictor[T]) access(e T) bool {
+	el, ok := l.index[e]
+	if ok && l.moveOnAccess {
+		l.order.MoveToFront(el)
+	}
+	return ok
+}
+
+func (l *listEvictor[T]) remove(e T) {
+	if el, ok := l.index[e]; ok {
+		l.order.Remove(el)
+		delete(l.index, e)
+	}
+}
+
+func (l *listEvictor[T]) evict() T {
+	e := l.order.Remove(l.order.Back()).(T)
+	delete(l.index, e)
+	return e
+}
+
+func (l *listEvictor[T]) len() int {
+	return len(l.index)
+}
+
+func (l *listEvictor[T]) each(fn func(T)) {
+	for k := range l.index {
+		fn(k)
+	}
+}
+
+// lfuEntry is an element of an lfuEvictor's heap
+type lfuEntry[T comparable] struct {
+	e     T
+	uses  uint64
+	last  uint64 // the tick of the last use, to break ties
+	index int
+}
+
+// lfuEvictor keeps its elements in a min-heap ordered by uses then last use
+type lfuEvictor[T comparable] struct {
+	index   map[T]*lfuEntry[T]
+	entries lfuHeap[T]
+	tick    uint64
+}
+
+func (l *lfuEvictor[T]) add(e T) {
+	l.tick++
+	entry := &lfuEntry[T]{e: e, uses: 1, last: l.tick}
+	l.index[e] = entry
+	heap.Push(&l.entries, entry)
+}
+
+func (l *lfuEvictor[T]) access(e T) bool {
+	entry, ok := l.index[e]
+	if ok {
+		l.tick++
+		entry.uses++
+		entry.last = l.tick
+		heap.Fix(&l.entries, entry.index)
+	}
+	return ok
+}
+
+func (l *lfuEvictor[T]) remove(e T) {
+	if entry, ok := l.index[e]; ok {
+		heap.Remove(&l.entries, entry.index)
+		delete(l.index, e)
+	}
+}
+
+func (l *lfuEvictor[T]) evict() T {
+	entry := heap.Pop(&l.entries).(*lfuEntry[T])
+	delete(l.index, entry.e)
+	return entry.e
+}
+
+func (l *lfuEvictor[T]) len() int {
+	return len(l.index)
+}
+
+func (l *lfuEvictor[T]) each(fn func(T)) {
+	for k := range l.index {
+		fn(k)
+	}
+}
+
+// lfuHeap implements heap.Interface for lfuEvictor
+type lfuHeap[T comparable] []*lfuEntry[T]
+
+func (h lfuHeap[T]) Len() int {
+	return len(h)
+}
+
+func (h lfuHeap[T]) Less(i, j int) bool {
+	if h[i].uses != h[j].uses {
+		return h[i].uses < h[j].uses
+	}
+	return h[i].last < h[j].last
+}
+
+func (h lfuHeap[T]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *lfuHeap[T]) Push(x any) {
+	entry := x.(*lfuEntry[T])
+	entry.index = len(*h)
+	*h = append(*h, entry)
+}
+
+func (h *lfuHeap[T]) Pop() any {
+	old := *h
+	entry := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return entry
+}
+
+// randomEvictor keeps its elements in a slice so it can evict a random one in constant time
+type randomEvictor[T comparable] struct {
+	index    map[T]int
+	elements []T
+}
+
+func (r *randomEvictor[T]) add(e T) {
+	r.index[e] = len(r.elements)
+	r.elements = append(r.elements, e)
+}
+
+func (r *randomEvictor[T]) access(e T) bool {
+	_, ok := r.index[e]
+	return ok
+}
+
+func (r *randomEvictor[T]) remove(e T) {
+	i, ok := r.index[e]
+	if !ok {
+		return
+	}
+	// move the last element into the hole
+	last := r.elements[len(r.elements)-1]
+	r.elements[i] = last
+	r.index[last] = i
+	r.elements = r.elements[:len(r.elements)-1]
+	delete(r.index, e)
+}
+
+func (r *randomEvictor[T]) evict() T {
+	e := r.elements[rand.Intn(len(r.elements))]
+	r.remove(e)
+	return e
+}
+
+func (r *randomEvictor[T]) len() int {
+	return len(r.index)
+}
+
+func (r *randomEvictor[T]) each(fn func(T)) {
+	for _, e := range r.elements {
+		fn(e)
+	}
+}
@@ -0,0 +1,154 @@
+package set
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/drkennetz/set/settest"
+)
+
+func TestBoundedSet_LRU(t *testing.T) {
+	s := NewBoundedSet[int](3, LRU)
+	var evicted []int
+	s.OnEvict(func(e int) {
+		evicted = append(evicted, e)
+	})
+	s.Add(1)
+	s.Add(2)
+	s.Add(3)
+	s.Contains(1)
+	s.Add(2)
+	s.Add(4)
+	s.Add(5)
+	settest.AssertEqual[int](t, s, NewSetFromSlice([]int{2, 4, 5}))
+	if len(evicted) != 2 || evicted[0] != 3 || evicted[1] != 1 {
+		t.Errorf("Expected 3 then 1 to be evicted, got %v", evicted)
+	}
+}
+
+func TestBoundedSet_FIFO(t *testing.T) {
+	s := NewBoundedSet[int](3, FIFO)
+	s.Add(1)
+	s.Add(2)
+	s.Add(3)
+	s.Contains(1)
+	s.Add(1)
+	s.Add(4)
+	settest.AssertEqual[int](t, s, NewSetFromSlice([]int{2, 3, 4}))
+}
+
+func TestBoundedSet_LFU(t *testing.T) {
+	s := NewBoundedSet[string](3, LFU)
+	s.Add("a")
+	s.Add("b")
+	s.Add("c")
+	s.Contains("a")
+	s.Contains("a")
+	s.Contains("c")
+	s.Add("d") // b has the fewest uses
+	settest.AssertEqual[string](t, s, NewSetFromSlice([]string{"a", "c", "d"}))
+	s.Add("e") // c and d have two and one uses, d goes
+	settest.AssertEqual[string](t, s, NewSetFromSlice([]string{"a", "c", "e"}))
+	s.Contains("e")
+	s.Add("f") // c and e both have two uses, c was used longer ago
+	settest.AssertEqual[string](t, s, NewSetFromSlice([]string{"a", "e", "f"}))
+	s.Remove("a")
+	s.Remove("missing")
+	s.Add("g")
+	s.Add("h") // f has one use
+	settest.AssertEqual[string](t, s, NewSetFromSlice([]string{"e", "g", "h"}))
+}
+
+func TestBoundedSet_Random(t *testing.T) {
+	s := NewBoundedSet[int](10, Random)
+	for i := 0; i < 100; i++ {
+		s.Add(i)
+		if s.Len() > 10 {
+			t.Fatalf("Expected at most 10 elements, got %d", s.Len())
+		}
+	}
+	if s.Stats().Evictions != 90 {
+		t.Errorf("Expected 90 evictions, got %d", s.Stats().Evictions)
+	}
+	for _, e := range s.ToSlice() {
+		s.Remove(e)
+	}
+	s.Remove(1000)
+	if !s.IsEmpty() {
+		t.Errorf("Expected empty set, got %v", s)
+	}
+}
+
+func TestBoundedSet_Stats(t *testing.T) {
+	s := NewBoundedSet[int](2, LRU)
+	s.Add(1)
+	s.Contains(1)
+	s.Contains(1)
+	s.Contains(2)
+	s.Add(2)
+	s.Add(3)
+	want := BoundedStats{Hits: 2, Misses: 1, Evictions: 1}
+	if s.Stats() != want {
+		t.Errorf("Expected %+v, got %+v", want, s.Stats())
+	}
+	s.Clear()
+	if !s.IsEmpty() || s.Cap() != 2 || s.Stats() != want {
+		t.Errorf("Expected an empty set with the stats kept, got %v %+v", s, s.Stats())
+	}
+	if s.String() != "[]" {
+		t.Errorf("Expected [], got %s", s)
+	}
+}
+
+func TestNewBoundedSet_Panics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewBoundedSet() did not panic for a capacity of zero")
+		}
+	}()
+	NewBoundedSet[int](0, LRU)
+}
+
+func TestEvictionPolicy_String(t *testing.T) {
+	for p, want := range map[EvictionPolicy]string{LRU: "LRU", LFU: "LFU", FIFO: "FIFO", Random: "Random", 9: "EvictionPolicy(9)"} {
+		if p.String() != want {
+			t.Errorf("Expected %s, got %s", want, p)
+		}
+	}
+}
+
+// go test -race -run TestThreadSafeBoundedSet .
+func TestThreadSafeBoundedSet(t *testing.T) {
+	s := NewThreadSafeBoundedSet[int](10, LFU)
+	var mu sync.Mutex
+	evicted := 0
+	s.OnEvict(func(int) {
+		mu.Lock()
+		defer mu.Unlock()
+		evicted++
+	})
+	var wg sync.WaitGroup
+	wg.Add(20)
+	for i := 0; i < 20; i++ {
+		go func(i int) {
+			defer wg.Done()
+			s.Add(i)
+			s.Contains(i)
+		}(i)
+	}
+	wg.Wait()
+	if s.Len() != 10 || s.Cap() != 10 || s.IsEmpty() {
+		t.Errorf("Expected 10 elements, got %v", s)
+	}
+	if evicted != 10 || s.Stats().Evictions != 10 {
+		t.Errorf("Expected 10 evictions, got %d and %+v", evicted, s.Stats())
+	}
+	if len(s.ToSlice()) != 10 {
+		t.Errorf("Expected 10 elements, got %v", s.ToSlice())
+	}
+	s.Remove(s.ToSlice()[0])
+	s.Clear()
+	if !s.IsEmpty() || s.String() != "[]" {
+		t.Errorf("Expected empty set, got %v", s)
+	}
+}
@@ -0,0 +1,190 @@
+package set
+
+// Iterator lazily produces a sequence of values
+// Call Next to advance to the next value and Value to read it:
+//
+//	for it := s.PowerSet(); it.Next(); {
+//		subset := it.Value()
+//	}
+type Iterator[E any] struct {
+	next  func() (E, bool)
+	value E
+}
+
+// Next advances the iterator and returns false once there are no more values
+func (it *Iterator[E]) Next() bool {
+	if it.next == nil {
+		return false
+	}
+	v, ok := it.next()
+	if !ok {
+		var zero E
+		it.next, it.value = nil, zero
+		return false
+	}
+	it.value = v
+	return true
+}
+
+// Value returns the current value, the zero value of E before the first call to Next or after the last one
+func (it *Iterator[E]) Value() E {
+	return it.value
+}
+
+// Pair is an element of a cartesian product
+type Pair[A, B comparable] struct {
+	First  A
+	Second B
+}
+
+// CartesianProduct returns every pair of an element of a and an element of b as a new set
+func CartesianProduct[A, B comparable](a *Set[A], b *Set[B]) *Set[Pair[A, B]] {
+	product := make(map[Pair[A, B]]struct{}, len(a.m)*len(b.m))
+	for x := range a.m {
+		for y := range b.m {
+			product[Pair[A, B]{x, y}] = struct{}{}
+		}
+	}
+	return &Set[Pair[A, B]]{m: product}
+}
+
+// Product lazily yields every tuple holding one element of each set, in the order of the sets
+// The elements are read when Product is called, and each tuple is a new slice
+// The product of no sets is a single empty tuple
+func Product[T comparable](sets ...*Set[T]) *Iterator[[]T] {
+	elems := make([][]T, len(sets))
+	for i, s := range sets {
+		elems[i] = s.ToSlice()
+		if len(elems[i]) == 0 {
+			return &Iterator[[]T]{}
+		}
+	}
+	indices := make([]int, len(sets))
+	done := false
+	return &Iterator[[]T]{next: func() ([]T, bool) {
+		if done {
+			return nil, false
+		}
+		tuple := make([]T, len(elems))
+		for i, j := range indices {
+			tuple[i] = elems[i][j]
+		}
+		// advance the indices like an odometer, the last set fastest
+		done = true
+		for i := len(indices) - 1; i >= 0; i-- {
+			if indices[i]++; indices[i] < len(elems[i]) {
+				done = false
+				break
+			}
+			indices[i] = 0
+		}
+		return tuple, true
+	}}
+}
+
+// PowerSet lazily yields every subset of the set as a new set, starting with the empty set
+// The elements are read when PowerSet is called; a set of n elements has 2^n subsets
+func (s *Set[T]) PowerSet() *Iterator[*Set[T]] {
+	elems := s.ToSlice()
+	included := make([]bool, len(elems))
+	done := false
+	return &Iterator[*Set[T]]{next: func() (*Set[T], bool) {
+		if done {
+			return nil, false
+		}
+		subset := s.empty()
+		for i, ok := range included {
+			if ok {
+				subset.m[elems[i]] = struct{}{}
+			}
+		}
+		// count up in binary, one bit per element
+		done = true
+		for i := range included {
+			if included[i] = !included[i]; included[i] {
+				done = false
+				break
+			}
+		}
+		return subset, true
+	}}
+}
+
+// Combinations lazily yields every subset of exactly k elements as a new set
+// The elements are read when Combinations is called, and there are none if k is negative or larger than the set
+func (s *Set[T]) Combinations(k int) *Iterator[*Set[T]] {
+	elems := s.ToSlice()
+	if k < 0 || k > len(elems) {
+		return &Iterator[*Set[T]]{}
+	}
+	indices := make([]int, k)
+	for i := range indices {
+		indices[i] = i
+	}
+	done := false
+	return &Iterator[*Set[T]]{next: func() (*Set[T], bool) {
+		if done {
+			return nil, false
+		}
+		subset := s.derived(make(map[T]struct{}, k))
+		for _, j := range indices {
+			subset.m[elems[j]] = struct{}{}
+		}
+		// advance the rightmost index that can still move, and reset the ones after it
+		done = true
+		for i := k - 1; i >= 0; i-- {
+			if indices[i] < len(elems)-k+i {
+				indices[i]++
+				for j := i + 1; j < k; j++ {
+					indices[j] = indices[j-1] + 1
+				}
+				done = false
+				break
+			}
+		}
+		return subset, true
+	}}
+}
+
+// Permutations lazily yields every ordering of the elements of the set, each as a new slice
+// The elements are read when Permutations is called; a set of n elements has n! orderings, so keep n small
+func (s *Set[T]) Permutations() *Iterator[[]T] {
+	elems := s.ToSlice()
+	indices := make([]int, len(elems))
+	for i := range indices {
+		indices[i] = i
+	}
+	done := false
+	return &Iterator[[]T]{next: func() ([]T, bool) {
+		if done {
+			return nil, false
+		}
+		perm := make([]T, len(elems))
+		for i, j := range indices {
+			perm[i] = elems[j]
+		}
+		done = !nextPermutation(indices)
+		return perm, true
+	}}
+}
+
+// nextPermutation rearranges indices into the next lexicographic permutation
+// It returns false if indices was already the last one
+func nextPermutation(indices []int) bool {
+	i := len(indices) - 2
+	for i >= 0 && indices[i] >= indices[i+1] {
+		i--
+	}
+	if i < 0 {
+		return false
+	}
+	j := len(indices) - 1
+	for indices[j] <= indices[i] {
+		j--
+	}
+	indices[i], indices[j] = indices[j], indices[i]
+	for l, r := i+1, len(indices)-1; l < r; l, r = l+1, r-1 {
+		indices[l], indices[r] = indices[r], indices[l]
+	}
+	return true
+}
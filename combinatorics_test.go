@@ -0,0 +1,120 @@
+package set
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/drkennetz/set/settest"
+)
+
+// canonical returns a string that is the same for sets with the same elements
+func canonical[T comparable](s *Set[T]) string {
+	elems := make([]string, 0, s.Len())
+	for _, e := range s.ToSlice() {
+		elems = append(elems, fmt.Sprint(e))
+	}
+	sort.Strings(elems)
+	return fmt.Sprint(elems)
+}
+
+func TestCartesianProduct(t *testing.T) {
+	browsers := NewSetFromSlice([]string{"firefox", "chrome"})
+	sizes := NewSetFromSlice([]int{320, 1024, 1920})
+	product := CartesianProduct(browsers, sizes)
+	if product.Len() != 6 {
+		t.Errorf("Expected 6 pairs, got %d", product.Len())
+	}
+	if !product.Contains(Pair[string, int]{"chrome", 1024}) {
+		t.Errorf("Expected {chrome 1024} in %v", product)
+	}
+	if !CartesianProduct(browsers, NewSet[int]()).IsEmpty() {
+		t.Error("Expected the product with an empty set to be empty")
+	}
+}
+
+func TestProduct(t *testing.T) {
+	a := NewSetFromSlice([]int{1, 2})
+	b := NewSetFromSlice([]int{10, 20, 30})
+	c := NewSetFromSlice([]int{100})
+	seen := NewSet[string]()
+	for it := Product(a, b, c); it.Next(); {
+		tuple := it.Value()
+		if len(tuple) != 3 || !a.Contains(tuple[0]) || !b.Contains(tuple[1]) || !c.Contains(tuple[2]) {
+			t.Errorf("Unexpected tuple %v", tuple)
+		}
+		seen.Add(fmt.Sprint(tuple))
+	}
+	if seen.Len() != 6 {
+		t.Errorf("Expected 6 distinct tuples, got %v", seen)
+	}
+	if it := Product(a, NewSet[int]()); it.Next() {
+		t.Errorf("Expected no tuples with an empty set, got %v", it.Value())
+	}
+	it := Product[int]()
+	if !it.Next() || len(it.Value()) != 0 || it.Next() {
+		t.Error("Expected a single empty tuple for no sets")
+	}
+}
+
+func TestSet_PowerSet(t *testing.T) {
+	s := NewSetFromSlice([]int{1, 2, 3, 4})
+	it := s.PowerSet()
+	if it.Value() != nil {
+		t.Errorf("Expected nil before Next, got %v", it.Value())
+	}
+	seen := NewSet[string]()
+	for it.Next() {
+		subset := it.Value()
+		settest.AssertSubset[int](t, subset, s)
+		seen.Add(canonical(subset))
+	}
+	if seen.Len() != 16 {
+		t.Errorf("Expected 16 distinct subsets, got %d", seen.Len())
+	}
+	if it.Next() || it.Value() != nil {
+		t.Error("Expected the iterator to stay exhausted")
+	}
+	it = NewSet[int]().PowerSet()
+	if !it.Next() || !it.Value().IsEmpty() || it.Next() {
+		t.Error("Expected the power set of the empty set to be {{}}")
+	}
+}
+
+func TestSet_Combinations(t *testing.T) {
+	s := NewSetFromSlice([]string{"a", "b", "c", "d", "e"})
+	for k, want := range []int{1, 5, 10, 10, 5, 1, 0} {
+		seen := NewSet[string]()
+		for it := s.Combinations(k); it.Next(); {
+			subset := it.Value()
+			if subset.Len() != k {
+				t.Errorf("Combinations(%d) yielded %v", k, subset)
+			}
+			settest.AssertSubset[string](t, subset, s)
+			seen.Add(canonical(subset))
+		}
+		if seen.Len() != want {
+			t.Errorf("Combinations(%d): expected %d distinct subsets, got %d", k, want, seen.Len())
+		}
+	}
+	if s.Combinations(-1).Next() {
+		t.Error("Expected no subsets of negative size")
+	}
+}
+
+func TestSet_Permutations(t *testing.T) {
+	s := NewSetFromSlice([]int{1, 2, 3, 4})
+	seen := NewSet[string]()
+	for it := s.Permutations(); it.Next(); {
+		perm := it.Value()
+		settest.AssertEqual[int](t, NewSetFromSlice(perm), s)
+		seen.Add(fmt.Sprint(perm))
+	}
+	if seen.Len() != 24 {
+		t.Errorf("Expected 24 distinct permutations, got %d", seen.Len())
+	}
+	it := NewSet[int]().Permutations()
+	if !it.Next() || len(it.Value()) != 0 || it.Next() {
+		t.Error("Expected a single empty permutation of the empty set")
+	}
+}
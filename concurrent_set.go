@@ -2,7 +2,9 @@ package set
 
 import (
 	"fmt"
+	"sort"
 	"sync"
+	"sync/atomic"
 )
 
 // ThreadSafeSet is a thread-safe set data structure
@@ -10,8 +12,10 @@ import (
 // We redefine a lot of code in this snippet because we don't want to lock and unlock the mutex
 // for every operation. We only lock and unlock the mutex once per operation.
 type ThreadSafeSet[T comparable] struct {
-	m map[T]struct{}
-	l sync.Mutex
+	m  map[T]struct{}
+	l  sync.Mutex
+	fp fingerprintState
+	id atomic.Uint64 // orders the set in lockAll, assigned on first use
 }
 
 // NewThreadSafeSet returns a new thread-safe set
@@ -34,7 +38,7 @@ func NewThreadSafeSetFromSlice[T comparable](s []T) *ThreadSafeSet[T] {
 func (s *ThreadSafeSet[T]) Add(e T) {
 	s.l.Lock()
 	defer s.l.Unlock()
-	s.m[e] = struct{}{}
+	s.insert(e)
 }
 
 // Contains returns true if the set contains the element
@@ -49,7 +53,7 @@ func (s *ThreadSafeSet[T]) Contains(e T) bool {
 func (s *ThreadSafeSet[T]) Remove(e T) {
 	s.l.Lock()
 	defer s.l.Unlock()
-	delete(s.m, e)
+	s.delete(e)
 }
 
 // Pop removes and returns an arbitrary element from the set or returns the zero value of T if the set is empty
@@ -60,18 +64,93 @@ func (s *ThreadSafeSet[T]) Pop() T {
 	for k := range s.m {
 		// subtle differences come in
 		// we don't want to lock and lock again so we recode
-		delete(s.m, k)
+		s.delete(k)
 		return k
 	}
 	return zero
 }
 
-// Intersection returns the intersection of two sets as a new set IE the values that are in both sets
-func (s *ThreadSafeSet[T]) Intersection(s2 *ThreadSafeSet[T]) *ThreadSafeSet[T] {
+// TryPop removes and returns an arbitrary element from the set and true, or the zero value of T and false if the set is empty
+func (s *ThreadSafeSet[T]) TryPop() (T, bool) {
+	s.l.Lock()
+	defer s.l.Unlock()
+	var zero T
+	for k := range s.m {
+		s.delete(k)
+		return k, true
+	}
+	return zero, false
+}
+
+// AddIfAbsent adds an element to the set and returns true if it was not already in the set
+func (s *ThreadSafeSet[T]) AddIfAbsent(e T) bool {
+	s.l.Lock()
+	defer s.l.Unlock()
+	if _, ok := s.m[e]; ok {
+		return false
+	}
+	s.insert(e)
+	return true
+}
+
+// RemoveIfPresent removes an element from the set and returns true if it was in the set
+func (s *ThreadSafeSet[T]) RemoveIfPresent(e T) bool {
 	s.l.Lock()
 	defer s.l.Unlock()
-	s2.l.Lock()
-	defer s2.l.Unlock()
+	if _, ok := s.m[e]; !ok {
+		return false
+	}
+	s.delete(e)
+	return true
+}
+
+// Move atomically moves an element from s to dst and returns true if it was in s, like Redis SMOVE
+// Nothing changes if the element is not in s. If it is already in dst it is only removed from s
+func (s *ThreadSafeSet[T]) Move(e T, dst *ThreadSafeSet[T]) bool {
+	unlock := lockAll(s, dst)
+	defer unlock()
+	if _, ok := s.m[e]; !ok {
+		return false
+	}
+	s.delete(e)
+	dst.insert(e)
+	return true
+}
+
+// Replace atomically replaces oldElem with newElem and returns true if oldElem was in the set
+// Nothing changes if oldElem is not in the set
+func (s *ThreadSafeSet[T]) Replace(oldElem, newElem T) bool {
+	s.l.Lock()
+	defer s.l.Unlock()
+	if _, ok := s.m[oldElem]; !ok {
+		return false
+	}
+	s.delete(oldElem)
+	s.insert(newElem)
+	return true
+}
+
+// Compute calls fn with whether the element is in the set and makes the element present
+// if fn returns true or absent if it returns false. fn runs under the set's lock, so the
+// check and the update are atomic; fn must not call methods of the set.
+// Compute returns the value returned by fn
+func (s *ThreadSafeSet[T]) Compute(e T, fn func(present bool) bool) bool {
+	s.l.Lock()
+	defer s.l.Unlock()
+	_, present := s.m[e]
+	keep := fn(present)
+	if keep {
+		s.insert(e)
+	} else {
+		s.delete(e)
+	}
+	return keep
+}
+
+// Intersection returns the intersection of two sets as a new set IE the values that are in both sets
+func (s *ThreadSafeSet[T]) Intersection(s2 *ThreadSafeSet[T]) *ThreadSafeSet[T] {
+	unlock := lockAll(s, s2)
+	defer unlock()
 	// make sure s is the smaller set
 	if len(s.m) > len(s2.m) {
 		s, s2 = s2, s
@@ -88,10 +167,8 @@ func (s *ThreadSafeSet[T]) Intersection(s2 *ThreadSafeSet[T]) *ThreadSafeSet[T]
 
 // Union returns the union of two sets as a new set IE all the values in both sets
 func (s *ThreadSafeSet[T]) Union(s2 *ThreadSafeSet[T]) *ThreadSafeSet[T] {
-	s.l.Lock()
-	defer s.l.Unlock()
-	s2.l.Lock()
-	defer s2.l.Unlock()
+	unlock := lockAll(s, s2)
+	defer unlock()
 	// we don't lock s3 because it is created here
 	s3 := NewThreadSafeSet[T]()
 	for k := range s.m {
@@ -105,10 +182,8 @@ func (s *ThreadSafeSet[T]) Union(s2 *ThreadSafeSet[T]) *ThreadSafeSet[T] {
 
 // Difference returns the difference of two sets as a new set IE all the values in the first set that are not in the second set
 func (s *ThreadSafeSet[T]) Difference(s2 *ThreadSafeSet[T]) *ThreadSafeSet[T] {
-	s.l.Lock()
-	defer s.l.Unlock()
-	s2.l.Lock()
-	defer s2.l.Unlock()
+	unlock := lockAll(s, s2)
+	defer unlock()
 	// we don't lock s3 because it is created here
 	s3 := NewThreadSafeSet[T]()
 	for k := range s.m {
@@ -121,10 +196,8 @@ func (s *ThreadSafeSet[T]) Difference(s2 *ThreadSafeSet[T]) *ThreadSafeSet[T] {
 
 // SymmetricDifference returns the symmetric difference of two sets as a new set IE all the values that are in one set but not both
 func (s *ThreadSafeSet[T]) SymmetricDifference(s2 *ThreadSafeSet[T]) *ThreadSafeSet[T] {
-	s.l.Lock()
-	defer s.l.Unlock()
-	s2.l.Lock()
-	defer s2.l.Unlock()
+	unlock := lockAll(s, s2)
+	defer unlock()
 	// we don't lock s3 because it is created here
 	s3 := NewThreadSafeSet[T]()
 	for k := range s.m {
@@ -140,12 +213,82 @@ func (s *ThreadSafeSet[T]) SymmetricDifference(s2 *ThreadSafeSet[T]) *ThreadSafe
 	return s3
 }
 
-// IsSubset returns true if the first set is a subset of the second set
-func (s *ThreadSafeSet[T]) IsSubset(s2 *ThreadSafeSet[T]) bool {
+// UnionWith adds the values in s2 to s in place
+func (s *ThreadSafeSet[T]) UnionWith(s2 *ThreadSafeSet[T]) {
+	unlock := lockAll(s, s2)
+	defer unlock()
+	for k := range s2.m {
+		s.insert(k)
+	}
+}
+
+// IntersectWith removes the values in s that are not in s2 in place
+func (s *ThreadSafeSet[T]) IntersectWith(s2 *ThreadSafeSet[T]) {
+	unlock := lockAll(s, s2)
+	defer unlock()
+	for k := range s.m {
+		if _, ok := s2.m[k]; !ok {
+			s.delete(k)
+		}
+	}
+}
+
+// DifferenceWith removes the values in s2 from s in place
+func (s *ThreadSafeSet[T]) DifferenceWith(s2 *ThreadSafeSet[T]) {
+	unlock := lockAll(s, s2)
+	defer unlock()
+	// iterate over the smaller set
+	if len(s.m) < len(s2.m) {
+		for k := range s.m {
+			if _, ok := s2.m[k]; ok {
+				s.delete(k)
+			}
+		}
+		return
+	}
+	for k := range s2.m {
+		s.delete(k)
+	}
+}
+
+// SymmetricDifferenceWith keeps the values that are in one of the sets, but not both, in s in place
+func (s *ThreadSafeSet[T]) SymmetricDifferenceWith(s2 *ThreadSafeSet[T]) {
+	unlock := lockAll(s, s2)
+	defer unlock()
+	for k := range s2.m {
+		if _, ok := s.m[k]; ok {
+			s.delete(k)
+		} else {
+			s.insert(k)
+		}
+	}
+}
+
+// RemoveIf removes the elements that pass the predicate in place and returns how many were removed
+func (s *ThreadSafeSet[T]) RemoveIf(predicate func(T) bool) int {
 	s.l.Lock()
 	defer s.l.Unlock()
-	s2.l.Lock()
-	defer s2.l.Unlock()
+	removed := 0
+	for k := range s.m {
+		if predicate(k) {
+			s.delete(k)
+			removed++
+		}
+	}
+	return removed
+}
+
+// RetainIf removes the elements that don't pass the predicate in place and returns how many were removed
+func (s *ThreadSafeSet[T]) RetainIf(predicate func(T) bool) int {
+	return s.RemoveIf(func(e T) bool {
+		return !predicate(e)
+	})
+}
+
+// IsSubset returns true if the first set is a subset of the second set
+func (s *ThreadSafeSet[T]) IsSubset(s2 *ThreadSafeSet[T]) bool {
+	unlock := lockAll(s, s2)
+	defer unlock()
 	for k := range s.m {
 		if _, ok := s2.m[k]; !ok {
 			return false
@@ -162,10 +305,8 @@ func (s *ThreadSafeSet[T]) IsSuperset(s2 *ThreadSafeSet[T]) bool {
 
 // IsDisjoint returns true if the two sets have no elements in common
 func (s *ThreadSafeSet[T]) IsDisjoint(s2 *ThreadSafeSet[T]) bool {
-	s.l.Lock()
-	defer s.l.Unlock()
-	s2.l.Lock()
-	defer s2.l.Unlock()
+	unlock := lockAll(s, s2)
+	defer unlock()
 	// make sure s is the smaller set
 	if len(s.m) > len(s2.m) {
 		s, s2 = s2, s
@@ -207,6 +348,7 @@ func (s *ThreadSafeSet[T]) Clear() {
 	s.l.Lock()
 	defer s.l.Unlock()
 	s.m = make(map[T]struct{})
+	s.fp.reset()
 }
 
 // IsEmpty returns true if the set is empty
@@ -262,6 +404,23 @@ func (s *ThreadSafeSet[T]) Reduce(fn func(T, T) T) T {
 	return result
 }
 
+// TryReduce returns the result of applying the function to each element, starting from an arbitrary element instead of the zero value of T
+// It returns the result and true, or the zero value of T and false if the set is empty
+func (s *ThreadSafeSet[T]) TryReduce(fn func(T, T) T) (T, bool) {
+	s.l.Lock()
+	defer s.l.Unlock()
+	var result T
+	first := true
+	for k := range s.m {
+		if first {
+			result, first = k, false
+			continue
+		}
+		result = fn(result, k)
+	}
+	return result, !first
+}
+
 // Any returns true if any of the elements in the set pass the predicate
 func (s *ThreadSafeSet[T]) Any(predicate func(T) bool) bool {
 	s.l.Lock()
@@ -290,3 +449,52 @@ func (s *ThreadSafeSet[T]) All(predicate func(T) bool) bool {
 func (s *ThreadSafeSet[T]) String() string {
 	return fmt.Sprintf("%v", s.ToSlice())
 }
+
+// insert adds an element, the lock must be held
+// Every write to the map goes through insert or delete to keep the fingerprint up to date
+func (s *ThreadSafeSet[T]) insert(e T) {
+	insertElem(s.m, &s.fp, e)
+}
+
+// delete removes an element, the lock must be held
+func (s *ThreadSafeSet[T]) delete(e T) {
+	deleteElem(s.m, &s.fp, e)
+}
+
+// lockSeq hands out the sequence IDs that lockAll orders sets by
+var lockSeq atomic.Uint64
+
+// lockID returns the sequence ID of the set, assigning the next one the first time it is called
+func (s *ThreadSafeSet[T]) lockID() uint64 {
+	if id := s.id.Load(); id != 0 {
+		return id
+	}
+	// if two goroutines race to assign an ID, the first one wins and the other's is unused
+	s.id.CompareAndSwap(0, lockSeq.Add(1))
+	return s.id.Load()
+}
+
+// lockAll locks every distinct set in sets and returns a function that unlocks them.
+// The sets are always locked in order of their sequence IDs, so two goroutines locking overlapping
+// groups of sets can't deadlock, and a set passed more than once is only locked once.
+func lockAll[T comparable](sets ...*ThreadSafeSet[T]) (unlock func()) {
+	ordered := make([]*ThreadSafeSet[T], 0, len(sets))
+	seen := make(map[*ThreadSafeSet[T]]struct{}, len(sets))
+	for _, s := range sets {
+		if _, ok := seen[s]; !ok {
+			seen[s] = struct{}{}
+			ordered = append(ordered, s)
+		}
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].lockID() < ordered[j].lockID()
+	})
+	for _, s := range ordered {
+		s.l.Lock()
+	}
+	return func() {
+		for i := len(ordered) - 1; i >= 0; i-- {
+			ordered[i].l.Unlock()
+		}
+	}
+}
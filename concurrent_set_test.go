@@ -5,6 +5,8 @@ import (
 	"strings"
 	"sync"
 	"testing"
+
+	"github.com/drkennetz/set/settest"
 )
 
 // go test -run TestNewThreadSafeSet .
@@ -114,6 +116,163 @@ func TestTSSPop(t *testing.T) {
 	}
 }
 
+// go test -race -run TestTSSTryPop .
+func TestTSSTryPop(t *testing.T) {
+	s := NewThreadSafeSet[int]()
+	for i := 0; i < 20; i++ {
+		s.Add(i)
+	}
+	popped := make(chan int, 40)
+	var wg sync.WaitGroup
+	wg.Add(40)
+	for i := 0; i < 40; i++ {
+		go func() {
+			defer wg.Done()
+			if v, ok := s.TryPop(); ok {
+				popped <- v
+			}
+		}()
+	}
+	wg.Wait()
+	close(popped)
+	seen := NewSet[int]()
+	for v := range popped {
+		seen.Add(v)
+	}
+	if seen.Len() != 20 || !s.IsEmpty() {
+		t.Errorf("Expected 20 distinct elements popped, got %v", seen)
+	}
+}
+
+// go test -race -run TestTSSAddIfAbsent .
+func TestTSSAddIfAbsent(t *testing.T) {
+	s := NewThreadSafeSet[int]()
+	var wg sync.WaitGroup
+	wins := make(chan bool, 20)
+	wg.Add(20)
+	for i := 0; i < 20; i++ {
+		go func() {
+			defer wg.Done()
+			wins <- s.AddIfAbsent(7)
+		}()
+	}
+	wg.Wait()
+	close(wins)
+	won := 0
+	for w := range wins {
+		if w {
+			won++
+		}
+	}
+	if won != 1 {
+		t.Errorf("Expected exactly one goroutine to add the element, got %d", won)
+	}
+	if !s.Contains(7) || s.Len() != 1 {
+		t.Errorf("Expected {7}, got %v", s)
+	}
+}
+
+// go test -race -run TestTSSRemoveIfPresent .
+func TestTSSRemoveIfPresent(t *testing.T) {
+	s := NewThreadSafeSetFromSlice([]int{1})
+	var wg sync.WaitGroup
+	wins := make(chan bool, 20)
+	wg.Add(20)
+	for i := 0; i < 20; i++ {
+		go func() {
+			defer wg.Done()
+			wins <- s.RemoveIfPresent(1)
+		}()
+	}
+	wg.Wait()
+	close(wins)
+	won := 0
+	for w := range wins {
+		if w {
+			won++
+		}
+	}
+	if won != 1 {
+		t.Errorf("Expected exactly one goroutine to remove the element, got %d", won)
+	}
+	if !s.IsEmpty() {
+		t.Errorf("Expected empty set, got %v", s)
+	}
+}
+
+// go test -race -run TestTSSMove .
+func TestTSSMove(t *testing.T) {
+	src := NewThreadSafeSet[int]()
+	dst := NewThreadSafeSet[int]()
+	for i := 0; i < 20; i++ {
+		src.Add(i)
+	}
+	var wg sync.WaitGroup
+	wg.Add(40)
+	for i := 0; i < 20; i++ {
+		go func(i int) {
+			defer wg.Done()
+			src.Move(i, dst)
+		}(i)
+		// moving back and forth in the opposite lock order must not deadlock
+		go func(i int) {
+			defer wg.Done()
+			dst.Move(i+100, src)
+		}(i)
+	}
+	wg.Wait()
+	if !src.IsEmpty() || dst.Len() != 20 {
+		t.Errorf("Expected all elements moved, got %v and %v", src, dst)
+	}
+	if src.Move(1, dst) {
+		t.Error("Expected Move() of a missing element to return false")
+	}
+	dst.Add(30)
+	if !dst.Move(30, dst) || !dst.Contains(30) {
+		t.Error("Expected Move() to the same set to keep the element")
+	}
+}
+
+// go test -race -run TestTSSReplace .
+func TestTSSReplace(t *testing.T) {
+	s := NewThreadSafeSetFromSlice([]int{1, 2})
+	if !s.Replace(1, 3) {
+		t.Error("Expected Replace() of a present element to return true")
+	}
+	if s.Contains(1) || !s.Contains(3) {
+		t.Errorf("Expected {2, 3}, got %v", s)
+	}
+	if s.Replace(1, 4) || s.Contains(4) {
+		t.Error("Expected Replace() of a missing element to change nothing")
+	}
+	if !s.Replace(2, 3) || s.Len() != 1 {
+		t.Errorf("Expected {3}, got %v", s)
+	}
+}
+
+// go test -race -run TestTSSCompute .
+func TestTSSCompute(t *testing.T) {
+	s := NewThreadSafeSet[int]()
+	// toggle the element 20 times, which leaves it absent
+	var wg sync.WaitGroup
+	wg.Add(20)
+	for i := 0; i < 20; i++ {
+		go func() {
+			defer wg.Done()
+			s.Compute(5, func(present bool) bool {
+				return !present
+			})
+		}()
+	}
+	wg.Wait()
+	if s.Contains(5) {
+		t.Errorf("Expected empty set, got %v", s)
+	}
+	if !s.Compute(5, func(present bool) bool { return true }) || !s.Contains(5) {
+		t.Error("Expected Compute() returning true to add the element")
+	}
+}
+
 // go test -race -run TestTSSIntersection .
 func TestTSSIntersection(t *testing.T) {
 	s := NewThreadSafeSet[int]()
@@ -238,6 +397,49 @@ func TestTSSSymmetricDifference(t *testing.T) {
 	}
 }
 
+// go test -race -run TestTSSInPlace .
+func TestTSSInPlace(t *testing.T) {
+	a := NewThreadSafeSet[int]()
+	b := NewThreadSafeSetFromSlice([]int{0, 1, 2, 3, 4})
+	var wg sync.WaitGroup
+	wg.Add(20)
+	for i := 0; i < 10; i++ {
+		// in-place operations in opposite directions must not deadlock
+		go func() {
+			defer wg.Done()
+			a.UnionWith(b)
+		}()
+		go func(i int) {
+			defer wg.Done()
+			b.UnionWith(NewThreadSafeSetFromSlice([]int{i}))
+			b.IntersectWith(b)
+		}(i)
+	}
+	wg.Wait()
+	a.UnionWith(b)
+	settest.AssertEqual[int](t, a, NewSetFromSlice([]int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}))
+
+	a.IntersectWith(NewThreadSafeSetFromSlice([]int{1, 2, 3, 4, 5, 6, 10}))
+	settest.AssertEqual[int](t, a, NewSetFromSlice([]int{1, 2, 3, 4, 5, 6}))
+	a.DifferenceWith(NewThreadSafeSetFromSlice([]int{1}))
+	settest.AssertEqual[int](t, a, NewSetFromSlice([]int{2, 3, 4, 5, 6}))
+	a.DifferenceWith(NewThreadSafeSetFromSlice([]int{2, 7, 8, 9, 10, 11}))
+	settest.AssertEqual[int](t, a, NewSetFromSlice([]int{3, 4, 5, 6}))
+	a.SymmetricDifferenceWith(NewThreadSafeSetFromSlice([]int{6, 7}))
+	settest.AssertEqual[int](t, a, NewSetFromSlice([]int{3, 4, 5, 7}))
+	if n := a.RemoveIf(func(x int) bool { return x == 3 }); n != 1 {
+		t.Errorf("Expected 1 element removed, got %d", n)
+	}
+	if n := a.RetainIf(func(x int) bool { return x > 4 }); n != 1 {
+		t.Errorf("Expected 1 element removed, got %d", n)
+	}
+	settest.AssertEqual[int](t, a, NewSetFromSlice([]int{5, 7}))
+	a.SymmetricDifferenceWith(a)
+	if !a.IsEmpty() {
+		t.Errorf("Expected empty set, got %v", a)
+	}
+}
+
 // go test -race -run TestTSSIsSubset .
 func TestTSSIsSubset(t *testing.T) {
 	s := NewThreadSafeSet[int]()
@@ -582,3 +784,42 @@ func TestTSSString(t *testing.T) {
 		}
 	}
 }
+
+// go test -race -run TestTSSLockOrder .
+func TestTSSLockOrder(t *testing.T) {
+	a := NewThreadSafeSetFromSlice([]int{1, 2})
+	b := NewThreadSafeSetFromSlice([]int{2, 3})
+	// every set gets one sequence ID, even when goroutines race to assign it
+	ids := make(chan uint64, 10)
+	var wg sync.WaitGroup
+	wg.Add(10)
+	for i := 0; i < 10; i++ {
+		go func() {
+			defer wg.Done()
+			ids <- a.lockID()
+		}()
+	}
+	wg.Wait()
+	close(ids)
+	for id := range ids {
+		if id != a.lockID() || id == 0 {
+			t.Errorf("Expected a single sequence ID %d, got %d", a.lockID(), id)
+		}
+	}
+	if a.lockID() == b.lockID() {
+		t.Errorf("Expected different sets to get different sequence IDs, got %d", a.lockID())
+	}
+	// opposite operand orders lock in the same order, so they can't deadlock
+	wg.Add(200)
+	for i := 0; i < 100; i++ {
+		go func() {
+			defer wg.Done()
+			a.Union(b)
+		}()
+		go func() {
+			defer wg.Done()
+			b.Intersection(a)
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,26 @@
+package set
+
+import (
+	"testing"
+
+	"github.com/drkennetz/set/settest"
+)
+
+func TestSet_Conformance(t *testing.T) {
+	settest.RunConformance(t, NewSet[int])
+}
+
+// go test -race -run TestTSSConformance .
+func TestTSSConformance(t *testing.T) {
+	settest.RunConformance(t, NewThreadSafeSet[int])
+}
+
+// go test -fuzz FuzzSet .
+func FuzzSet(f *testing.F) {
+	settest.FuzzConformance(f, NewSet[int])
+}
+
+// go test -fuzz FuzzTSS .
+func FuzzTSS(f *testing.F) {
+	settest.FuzzConformance(f, NewThreadSafeSet[int])
+}
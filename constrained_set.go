@@ -0,0 +1,192 @@
+package set
+
+import "fmt"
+
+// Constraint admits an element to a ConstrainedSet
+// It returns the element to store, which lets it normalize the element, or an error to reject it
+type Constraint[T comparable] func(T) (T, error)
+
+// Validate returns a constraint that rejects the elements fn returns an error for
+func Validate[T comparable](fn func(T) error) Constraint[T] {
+	return func(e T) (T, error) {
+		return e, fn(e)
+	}
+}
+
+// Normalize returns a constraint that stores fn(e) in place of e
+func Normalize[T comparable](fn func(T) T) Constraint[T] {
+	return func(e T) (T, error) {
+		return fn(e), nil
+	}
+}
+
+// ConstraintError is returned when a constraint rejects an element
+type ConstraintError[T comparable] struct {
+	Elem T
+	Err  error
+}
+
+// Error returns the element and the reason it was rejected
+func (e *ConstraintError[T]) Error() string {
+	return fmt.Sprintf("set: element %v rejected: %v", e.Elem, e.Err)
+}
+
+// Unwrap returns the error returned by the constraint
+func (e *ConstraintError[T]) Unwrap() error {
+	return e.Err
+}
+
+// ConstrainedSet is a set whose elements all satisfy a chain of constraints.
+// Every element is run through the constraints, in order, before it is stored,
+// and every set the methods return is held to the same constraints.
+type ConstrainedSet[T comparable] struct {
+	s           *Set[T]
+	constraints []Constraint[T]
+}
+
+// NewConstrainedSet returns a new constrained set
+func NewConstrainedSet[T comparable](constraints ...Constraint[T]) *ConstrainedSet[T] {
+	return &ConstrainedSet[T]{
+		s:           NewSet[T](),
+		constraints: constraints,
+	}
+}
+
+// NewConstrainedSetFromSlice returns a new constrained set from a slice
+// It returns the first ConstraintError if an element is rejected
+func NewConstrainedSetFromSlice[T comparable](s []T, constraints ...Constraint[T]) (*ConstrainedSet[T], error) {
+	set := NewConstrainedSet(constraints...)
+	for _, v := range s {
+		if err := set.TryAdd(v); err != nil {
+			return nil, err
+		}
+	}
+	return set, nil
+}
+
+// TryAdd adds an element to the set, or returns a ConstraintError if a constraint rejects it
+func (c *ConstrainedSet[T]) TryAdd(e T) error {
+	admitted, err := c.admit(e)
+	if err != nil {
+		return err
+	}
+	c.s.Add(admitted)
+	return nil
+}
+
+// Add adds an element to the set if the constraints admit it, use TryAdd to find out why an element was rejected
+func (c *ConstrainedSet[T]) Add(e T) {
+	_ = c.TryAdd(e)
+}
+
+// Contains returns true if the set contains the element after normalization
+func (c *ConstrainedSet[T]) Contains(e T) bool {
+	admitted, err := c.admit(e)
+	return err == nil && c.s.Contains(admitted)
+}
+
+// Remove removes an element from the set after normalization
+func (c *ConstrainedSet[T]) Remove(e T) {
+	if admitted, err := c.admit(e); err == nil {
+		c.s.Remove(admitted)
+	}
+}
+
+// Pop removes and returns an arbitrary element from the set or returns the zero value of T if the set is empty
+func (c *ConstrainedSet[T]) Pop() T {
+	return c.s.Pop()
+}
+
+// Intersection returns the elements in both sets as a new set with the constraints of c
+func (c *ConstrainedSet[T]) Intersection(c2 *ConstrainedSet[T]) *ConstrainedSet[T] {
+	return c.with(c.s.Intersection(c2.s))
+}
+
+// Union returns the elements in either set as a new set with the constraints of c
+// The elements of c2 are admitted by the constraints of c, and the first one rejected is returned as a ConstraintError
+func (c *ConstrainedSet[T]) Union(c2 *ConstrainedSet[T]) (*ConstrainedSet[T], error) {
+	c3 := c.Copy()
+	for k := range c2.s.m {
+		if err := c3.TryAdd(k); err != nil {
+			return nil, err
+		}
+	}
+	return c3, nil
+}
+
+// Difference returns the elements in c that are not in c2 as a new set with the constraints of c
+func (c *ConstrainedSet[T]) Difference(c2 *ConstrainedSet[T]) *ConstrainedSet[T] {
+	return c.with(c.s.Difference(c2.s))
+}
+
+// Filter returns a new set with the constraints of c containing only the elements that satisfy the predicate
+func (c *ConstrainedSet[T]) Filter(predicate func(T) bool) *ConstrainedSet[T] {
+	return c.with(c.s.Filter(predicate))
+}
+
+// Map returns a new set with the constraints of c containing the results of applying the function to each element
+// The results are admitted by the constraints, and the first one rejected is returned as a ConstraintError
+func (c *ConstrainedSet[T]) Map(f func(T) T) (*ConstrainedSet[T], error) {
+	c2 := c.with(NewSet[T]())
+	for k := range c.s.m {
+		if err := c2.TryAdd(f(k)); err != nil {
+			return nil, err
+		}
+	}
+	return c2, nil
+}
+
+// Copy returns a copy of the set with the same constraints
+func (c *ConstrainedSet[T]) Copy() *ConstrainedSet[T] {
+	return c.with(c.s.Copy())
+}
+
+// Len returns the number of elements in the set
+func (c *ConstrainedSet[T]) Len() int {
+	return c.s.Len()
+}
+
+// Clear removes all elements from the set
+func (c *ConstrainedSet[T]) Clear() {
+	c.s.Clear()
+}
+
+// IsEmpty returns true if the set is empty
+func (c *ConstrainedSet[T]) IsEmpty() bool {
+	return c.s.IsEmpty()
+}
+
+// ToSlice returns a slice of the elements in the set
+func (c *ConstrainedSet[T]) ToSlice() []T {
+	return c.s.ToSlice()
+}
+
+// ToSet returns the elements of the set as a new, unconstrained set
+func (c *ConstrainedSet[T]) ToSet() *Set[T] {
+	return c.s.Copy()
+}
+
+// String returns a string representation of the set
+func (c *ConstrainedSet[T]) String() string {
+	return c.s.String()
+}
+
+// admit runs the element through the constraints
+func (c *ConstrainedSet[T]) admit(e T) (T, error) {
+	admitted := e
+	for _, constraint := range c.constraints {
+		var err error
+		if admitted, err = constraint(admitted); err != nil {
+			return admitted, &ConstraintError[T]{Elem: e, Err: err}
+		}
+	}
+	return admitted, nil
+}
+
+// with returns a constrained set with the constraints of c holding s, whose elements must already be admitted
+func (c *ConstrainedSet[T]) with(s *Set[T]) *ConstrainedSet[T] {
+	return &ConstrainedSet[T]{
+		s:           s,
+		constraints: c.constraints,
+	}
+}
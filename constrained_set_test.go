@@ -0,0 +1,143 @@
+package set
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/drkennetz/set/settest"
+)
+
+var errNotPositive = errors.New("not positive")
+
+func positive(e int) error {
+	if e <= 0 {
+		return errNotPositive
+	}
+	return nil
+}
+
+func hostnames() []Constraint[string] {
+	return []Constraint[string]{
+		Normalize(strings.TrimSpace),
+		Normalize(strings.ToLower),
+		Validate(func(e string) error {
+			if e == "" || strings.ContainsAny(e, " /") {
+				return errors.New("invalid hostname")
+			}
+			return nil
+		}),
+	}
+}
+
+func TestConstrainedSet_TryAdd(t *testing.T) {
+	c := NewConstrainedSet(Validate(positive))
+	if err := c.TryAdd(1); err != nil {
+		t.Errorf("ConstrainedSet.TryAdd() returned %v for a valid element", err)
+	}
+	err := c.TryAdd(-1)
+	var cerr *ConstraintError[int]
+	if !errors.As(err, &cerr) || cerr.Elem != -1 || !errors.Is(err, errNotPositive) {
+		t.Errorf("Expected a ConstraintError for -1 wrapping %v, got %v", errNotPositive, err)
+	}
+	if err.Error() != "set: element -1 rejected: not positive" {
+		t.Errorf("Unexpected error message %q", err)
+	}
+	c.Add(0)
+	c.Add(2)
+	settest.AssertEqual[int](t, c, NewSetFromSlice([]int{1, 2}))
+}
+
+func TestConstrainedSet_Normalize(t *testing.T) {
+	c := NewConstrainedSet(hostnames()...)
+	c.Add(" Example.COM ")
+	c.Add("example.com")
+	c.Add("not a host")
+	settest.AssertEqual[string](t, c, NewSetFromSlice([]string{"example.com"}))
+	if !c.Contains("EXAMPLE.com") {
+		t.Error("ConstrainedSet.Contains() failed to normalize the element")
+	}
+	if c.Contains("not a host") {
+		t.Error("ConstrainedSet.Contains() returned true for a rejected element")
+	}
+	c.Remove("not a host")
+	c.Remove("Example.com")
+	if !c.IsEmpty() || c.Len() != 0 {
+		t.Errorf("ConstrainedSet.Remove() failed to normalize the element, got %v", c)
+	}
+}
+
+func TestNewConstrainedSetFromSlice(t *testing.T) {
+	c, err := NewConstrainedSetFromSlice([]string{"A.com", "b.com"}, hostnames()...)
+	if err != nil {
+		t.Fatalf("NewConstrainedSetFromSlice() returned %v", err)
+	}
+	settest.AssertEqual[string](t, c, NewSetFromSlice([]string{"a.com", "b.com"}))
+	if _, err := NewConstrainedSetFromSlice([]string{"a.com", "a b"}, hostnames()...); err == nil {
+		t.Error("NewConstrainedSetFromSlice() accepted an invalid element")
+	}
+}
+
+func TestConstrainedSet_Union(t *testing.T) {
+	a, _ := NewConstrainedSetFromSlice([]int{1, 2}, Validate(positive))
+	b, _ := NewConstrainedSetFromSlice([]int{2, 3})
+	c, err := a.Union(b)
+	if err != nil {
+		t.Fatalf("ConstrainedSet.Union() returned %v", err)
+	}
+	settest.AssertEqual[int](t, c, NewSetFromSlice([]int{1, 2, 3}))
+	b.Add(-4)
+	if _, err := a.Union(b); !errors.Is(err, errNotPositive) {
+		t.Errorf("Expected ConstrainedSet.Union() to reject -4, got %v", err)
+	}
+	// the result keeps the constraints of the receiver
+	c.Add(-5)
+	if c.Contains(-5) {
+		t.Error("ConstrainedSet.Union() result lost the constraints")
+	}
+}
+
+func TestConstrainedSet_Map(t *testing.T) {
+	a, _ := NewConstrainedSetFromSlice([]int{1, 2, 3}, Validate(positive))
+	b, err := a.Map(func(e int) int { return e * 10 })
+	if err != nil {
+		t.Fatalf("ConstrainedSet.Map() returned %v", err)
+	}
+	settest.AssertEqual[int](t, b, NewSetFromSlice([]int{10, 20, 30}))
+	if _, err := a.Map(func(e int) int { return e - 2 }); !errors.Is(err, errNotPositive) {
+		t.Errorf("Expected ConstrainedSet.Map() to reject non-positive results, got %v", err)
+	}
+}
+
+func TestConstrainedSet_Operations(t *testing.T) {
+	a, _ := NewConstrainedSetFromSlice([]int{1, 2, 3, 4}, Validate(positive))
+	b, _ := NewConstrainedSetFromSlice([]int{3, 4, 5}, Validate(positive))
+	settest.AssertEqual[int](t, a.Intersection(b), NewSetFromSlice([]int{3, 4}))
+	settest.AssertEqual[int](t, a.Difference(b), NewSetFromSlice([]int{1, 2}))
+	even := a.Filter(func(e int) bool { return e%2 == 0 })
+	settest.AssertEqual[int](t, even, NewSetFromSlice([]int{2, 4}))
+	even.Add(-2)
+	if even.Contains(-2) {
+		t.Error("ConstrainedSet.Filter() result lost the constraints")
+	}
+	cp := a.Copy()
+	cp.Add(9)
+	if a.Contains(9) {
+		t.Error("ConstrainedSet.Copy() shares elements with the original")
+	}
+	plain := a.ToSet()
+	plain.Add(-1)
+	if a.Contains(-1) || !plain.Contains(-1) {
+		t.Error("ConstrainedSet.ToSet() did not return an unconstrained copy")
+	}
+	if e := a.Pop(); e < 1 || e > 4 || a.Len() != 3 {
+		t.Errorf("ConstrainedSet.Pop() returned %d", e)
+	}
+	if !strings.HasPrefix(a.String(), "[") {
+		t.Errorf("ConstrainedSet.String() returned %q", a.String())
+	}
+	a.Clear()
+	if !a.IsEmpty() {
+		t.Errorf("Expected empty set, got %v", a)
+	}
+}
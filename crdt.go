@@ -0,0 +1,295 @@
+package set
+
+import "fmt"
+
+// GSet is a grow-only set that replicas can merge in any order and converge.
+// Elements can be added but never removed. Like Set, it is not safe for concurrent use.
+type GSet[T comparable] struct {
+	s     *Set[T]
+	delta *Set[T]
+}
+
+// NewGSet returns a new grow-only set
+func NewGSet[T comparable]() *GSet[T] {
+	return &GSet[T]{
+		s:     NewSet[T](),
+		delta: NewSet[T](),
+	}
+}
+
+// Add adds an element to the set
+func (g *GSet[T]) Add(e T) {
+	if !g.s.Contains(e) {
+		g.s.Add(e)
+		g.delta.Add(e)
+	}
+}
+
+// Contains returns true if the set contains the element
+func (g *GSet[T]) Contains(e T) bool {
+	return g.s.Contains(e)
+}
+
+// Merge adds the elements of another replica to the set
+func (g *GSet[T]) Merge(g2 *GSet[T]) {
+	g.s.UnionWith(g2.s)
+}
+
+// TakeDelta returns the elements added to this replica since the last call as a new set
+// Merging the deltas of a replica into another has the same effect as merging the replica itself
+func (g *GSet[T]) TakeDelta() *GSet[T] {
+	delta := &GSet[T]{s: g.delta, delta: NewSet[T]()}
+	g.delta = NewSet[T]()
+	return delta
+}
+
+// Len returns the number of elements in the set
+func (g *GSet[T]) Len() int {
+	return g.s.Len()
+}
+
+// ToSlice returns a slice of the elements in the set
+func (g *GSet[T]) ToSlice() []T {
+	return g.s.ToSlice()
+}
+
+// Set returns the elements of the set as a new Set
+func (g *GSet[T]) Set() *Set[T] {
+	return g.s.Copy()
+}
+
+// String returns a string representation of the set
+func (g *GSet[T]) String() string {
+	return g.s.String()
+}
+
+// TwoPhaseSet is a replicated set whose elements can be removed once.
+// A removed element stays in the set as a tombstone and can't be added again;
+// a removal wins over a concurrent add. Like Set, it is not safe for concurrent use.
+type TwoPhaseSet[T comparable] struct {
+	added   *GSet[T]
+	removed *GSet[T]
+}
+
+// NewTwoPhaseSet returns a new two-phase set
+func NewTwoPhaseSet[T comparable]() *TwoPhaseSet[T] {
+	return &TwoPhaseSet[T]{
+		added:   NewGSet[T](),
+		removed: NewGSet[T](),
+	}
+}
+
+// Add adds an element to the set and returns false if it was removed before and can't be added again
+func (p *TwoPhaseSet[T]) Add(e T) bool {
+	if p.removed.Contains(e) {
+		return false
+	}
+	p.added.Add(e)
+	return true
+}
+
+// Remove removes an element from the set for good, and returns false if the set didn't contain it
+func (p *TwoPhaseSet[T]) Remove(e T) bool {
+	if !p.Contains(e) {
+		return false
+	}
+	p.removed.Add(e)
+	return true
+}
+
+// Contains returns true if the element was added and not removed
+func (p *TwoPhaseSet[T]) Contains(e T) bool {
+	return p.added.Contains(e) && !p.removed.Contains(e)
+}
+
+// Merge merges the adds and removes of another replica into the set
+func (p *TwoPhaseSet[T]) Merge(p2 *TwoPhaseSet[T]) {
+	p.added.Merge(p2.added)
+	p.removed.Merge(p2.removed)
+}
+
+// TakeDelta returns the adds and removes made on this replica since the last call as a new set
+// Merging the deltas of a replica into another has the same effect as merging the replica itself
+func (p *TwoPhaseSet[T]) TakeDelta() *TwoPhaseSet[T] {
+	return &TwoPhaseSet[T]{
+		added:   p.added.TakeDelta(),
+		removed: p.removed.TakeDelta(),
+	}
+}
+
+// Len returns the number of elements in the set
+func (p *TwoPhaseSet[T]) Len() int {
+	return p.Set().Len()
+}
+
+// ToSlice returns a slice of the elements in the set
+func (p *TwoPhaseSet[T]) ToSlice() []T {
+	return p.Set().ToSlice()
+}
+
+// Set returns the elements of the set as a new Set
+func (p *TwoPhaseSet[T]) Set() *Set[T] {
+	return p.added.s.Difference(p.removed.s)
+}
+
+// String returns a string representation of the set
+func (p *TwoPhaseSet[T]) String() string {
+	return p.Set().String()
+}
+
+// Tag identifies one add of an element to an ORSet
+type Tag struct {
+	Replica string
+	Seq     uint64
+}
+
+// String returns the tag as replica:seq
+func (t Tag) String() string {
+	return fmt.Sprintf("%s:%d", t.Replica, t.Seq)
+}
+
+// ORSet is an observed-remove set, a replicated set whose elements can be added and removed any number of times.
+// Every add is tagged with the replica ID and a sequence number, and a remove only removes the adds its
+// replica has seen, so an add wins over a concurrent remove. The tags of removed adds are kept as tombstones.
+// Every replica must have a unique ID. Like Set, it is not safe for concurrent use.
+type ORSet[T comparable] struct {
+	replica string
+	seq     uint64
+	// adds holds the tags of every add that hasn't been removed
+	adds       map[T]map[Tag]struct{}
+	tombstones map[Tag]struct{}
+	delta      *ORSet[T]
+}
+
+// NewORSet returns a new observed-remove set for the replica
+func NewORSet[T comparable](replica string) *ORSet[T] {
+	return &ORSet[T]{
+		replica:    replica,
+		adds:       make(map[T]map[Tag]struct{}),
+		tombstones: make(map[Tag]struct{}),
+	}
+}
+
+// Replica returns the ID of the replica
+func (o *ORSet[T]) Replica() string {
+	return o.replica
+}
+
+// Add adds an element to the set with a new tag
+func (o *ORSet[T]) Add(e T) {
+	o.seq++
+	tag := Tag{Replica: o.replica, Seq: o.seq}
+	o.addTag(e, tag)
+	o.pending().addTag(e, tag)
+}
+
+// Remove removes every add of the element this replica has seen, and returns false if the set didn't contain it
+func (o *ORSet[T]) Remove(e T) bool {
+	tags, ok := o.adds[e]
+	if !ok {
+		return false
+	}
+	delete(o.adds, e)
+	delta := o.pending()
+	delete(delta.adds, e)
+	for tag := range tags {
+		o.tombstones[tag] = struct{}{}
+		delta.tombstones[tag] = struct{}{}
+	}
+	return true
+}
+
+// Contains returns true if the set contains an add of the element that hasn't been removed
+func (o *ORSet[T]) Contains(e T) bool {
+	_, ok := o.adds[e]
+	return ok
+}
+
+// Merge merges the adds and removes of another replica into the set
+func (o *ORSet[T]) Merge(o2 *ORSet[T]) {
+	for tag := range o2.tombstones {
+		o.tombstones[tag] = struct{}{}
+		o.observe(tag)
+	}
+	for e, tags := range o2.adds {
+		for tag := range tags {
+			o.addTag(e, tag)
+			o.observe(tag)
+		}
+	}
+	if len(o2.tombstones) == 0 {
+		return
+	}
+	// drop the adds the other replica removed
+	for e, tags := range o.adds {
+		for tag := range tags {
+			if _, ok := o.tombstones[tag]; ok {
+				delete(tags, tag)
+			}
+		}
+		if len(tags) == 0 {
+			delete(o.adds, e)
+		}
+	}
+}
+
+// TakeDelta returns the adds and removes made on this replica since the last call as a new set
+// Merging the deltas of a replica into another has the same effect as merging the replica itself
+func (o *ORSet[T]) TakeDelta() *ORSet[T] {
+	delta := o.pending()
+	o.delta = nil
+	return delta
+}
+
+// Len returns the number of elements in the set
+func (o *ORSet[T]) Len() int {
+	return len(o.adds)
+}
+
+// ToSlice returns a slice of the elements in the set
+func (o *ORSet[T]) ToSlice() []T {
+	slice := make([]T, 0, len(o.adds))
+	for e := range o.adds {
+		slice = append(slice, e)
+	}
+	return slice
+}
+
+// Set returns the elements of the set as a new Set
+func (o *ORSet[T]) Set() *Set[T] {
+	return NewSetFromSlice(o.ToSlice())
+}
+
+// String returns a string representation of the set
+func (o *ORSet[T]) String() string {
+	return fmt.Sprintf("%v", o.ToSlice())
+}
+
+// addTag records an add of e unless it was removed
+func (o *ORSet[T]) addTag(e T, tag Tag) {
+	if _, ok := o.tombstones[tag]; ok {
+		return
+	}
+	tags, ok := o.adds[e]
+	if !ok {
+		tags = make(map[Tag]struct{})
+		o.adds[e] = tags
+	}
+	tags[tag] = struct{}{}
+}
+
+// observe makes sure the replica never reuses a tag of its own it gets back from another replica,
+// which happens when a replica restarts from an empty state and merges its old state back in
+func (o *ORSet[T]) observe(tag Tag) {
+	if tag.Replica == o.replica && tag.Seq > o.seq {
+		o.seq = tag.Seq
+	}
+}
+
+// pending returns the delta of the changes since the last TakeDelta, creating it if needed
+func (o *ORSet[T]) pending() *ORSet[T] {
+	if o.delta == nil {
+		o.delta = NewORSet[T](o.replica)
+	}
+	return o.delta
+}
@@ -0,0 +1,275 @@
+package set
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/drkennetz/set/settest"
+)
+
+// crdt is the method set shared by the replicated sets, for the property tests
+type crdt[S any] interface {
+	Merge(S)
+	TakeDelta() S
+	Set() *Set[int]
+}
+
+// replicaOp makes a random change to a replica
+type replicaOp[S any] func(r *rand.Rand, s S)
+
+// checkMergeLaws checks that merging random replicas is commutative, associative and idempotent
+func checkMergeLaws[S crdt[S]](t *testing.T, newReplica func(id string) S, op replicaOp[S]) {
+	t.Helper()
+	r := rand.New(rand.NewSource(1))
+	random := func(id string) S {
+		s := newReplica(id)
+		for i := 0; i < 20; i++ {
+			op(r, s)
+		}
+		return s
+	}
+	merged := func(replicas ...S) *Set[int] {
+		s := newReplica("merged")
+		for _, replica := range replicas {
+			s.Merge(replica)
+		}
+		return s.Set()
+	}
+	for i := 0; i < 50; i++ {
+		a, b, c := random("a"), random("b"), random("c")
+		settest.AssertEqual[int](t, merged(a, b), merged(b, a))
+		settest.AssertEqual[int](t, merged(merged2(newReplica, a, b), c), merged(a, merged2(newReplica, b, c)))
+		settest.AssertEqual[int](t, merged(a, a, a), merged(a))
+		// merging into a replica itself is idempotent too
+		before := a.Set()
+		a.Merge(a)
+		settest.AssertEqual[int](t, a.Set(), before)
+	}
+}
+
+// merged2 returns a new replica holding the merge of a and b
+func merged2[S crdt[S]](newReplica func(id string) S, a, b S) S {
+	s := newReplica("merged")
+	s.Merge(a)
+	s.Merge(b)
+	return s
+}
+
+// checkConvergence runs random schedules of changes, lost deltas and full merges between
+// replicas, and checks that they all hold the same elements once they have all merged
+func checkConvergence[S crdt[S]](t *testing.T, newReplica func(id string) S, op replicaOp[S]) {
+	t.Helper()
+	for seed := int64(0); seed < 20; seed++ {
+		r := rand.New(rand.NewSource(seed))
+		replicas := make([]S, 4)
+		for i := range replicas {
+			replicas[i] = newReplica(fmt.Sprintf("r%d", i))
+		}
+		for step := 0; step < 200; step++ {
+			i, j := r.Intn(len(replicas)), r.Intn(len(replicas))
+			switch r.Intn(4) {
+			case 0, 1:
+				op(r, replicas[i])
+			case 2:
+				// deltas can get lost or arrive at only some replicas
+				delta := replicas[i].TakeDelta()
+				for _, other := range replicas {
+					if r.Intn(2) == 0 {
+						other.Merge(delta)
+					}
+				}
+			case 3:
+				replicas[j].Merge(replicas[i])
+			}
+		}
+		for _, a := range replicas {
+			for _, b := range replicas {
+				b.Merge(a)
+			}
+		}
+		for _, s := range replicas[1:] {
+			settest.AssertEqual[int](t, s.Set(), replicas[0].Set())
+		}
+	}
+}
+
+// checkDeltaConvergence checks that replicas exchanging only deltas, delivered in any order, converge
+func checkDeltaConvergence[S crdt[S]](t *testing.T, newReplica func(id string) S, op replicaOp[S]) {
+	t.Helper()
+	for seed := int64(0); seed < 20; seed++ {
+		r := rand.New(rand.NewSource(seed))
+		replicas := make([]S, 3)
+		inboxes := make([][]S, len(replicas))
+		for i := range replicas {
+			replicas[i] = newReplica(fmt.Sprintf("r%d", i))
+		}
+		for step := 0; step < 200; step++ {
+			i := r.Intn(len(replicas))
+			if r.Intn(3) > 0 {
+				op(r, replicas[i])
+				continue
+			}
+			delta := replicas[i].TakeDelta()
+			for j := range replicas {
+				if j != i {
+					inboxes[j] = append(inboxes[j], delta)
+				}
+			}
+			// deliver a random pending delta somewhere
+			if j := r.Intn(len(replicas)); len(inboxes[j]) > 0 {
+				k := r.Intn(len(inboxes[j]))
+				replicas[j].Merge(inboxes[j][k])
+				inboxes[j] = append(inboxes[j][:k], inboxes[j][k+1:]...)
+			}
+		}
+		for i := range replicas {
+			delta := replicas[i].TakeDelta()
+			for j := range replicas {
+				if j != i {
+					inboxes[j] = append(inboxes[j], delta)
+				}
+			}
+		}
+		for j := range replicas {
+			r.Shuffle(len(inboxes[j]), func(a, b int) {
+				inboxes[j][a], inboxes[j][b] = inboxes[j][b], inboxes[j][a]
+			})
+			for _, delta := range inboxes[j] {
+				replicas[j].Merge(delta)
+			}
+		}
+		for _, s := range replicas[1:] {
+			settest.AssertEqual[int](t, s.Set(), replicas[0].Set())
+		}
+	}
+}
+
+func newGSet(string) *GSet[int] {
+	return NewGSet[int]()
+}
+
+func gSetOp(r *rand.Rand, s *GSet[int]) {
+	s.Add(r.Intn(20))
+}
+
+func newTwoPhaseSet(string) *TwoPhaseSet[int] {
+	return NewTwoPhaseSet[int]()
+}
+
+func twoPhaseSetOp(r *rand.Rand, s *TwoPhaseSet[int]) {
+	if r.Intn(3) == 0 {
+		s.Remove(r.Intn(20))
+	} else {
+		s.Add(r.Intn(20))
+	}
+}
+
+func newORSet(id string) *ORSet[int] {
+	return NewORSet[int](id)
+}
+
+func orSetOp(r *rand.Rand, s *ORSet[int]) {
+	if r.Intn(3) == 0 {
+		s.Remove(r.Intn(10))
+	} else {
+		s.Add(r.Intn(10))
+	}
+}
+
+func TestGSet(t *testing.T) {
+	a, b := NewGSet[string](), NewGSet[string]()
+	a.Add("x")
+	a.Add("x")
+	b.Add("y")
+	a.Merge(b)
+	if a.Len() != 2 || !a.Contains("y") || len(a.ToSlice()) != 2 {
+		t.Errorf("Expected [x y], got %v", a)
+	}
+	if d := a.TakeDelta(); d.Len() != 1 || !d.Contains("x") {
+		t.Errorf("Expected the delta [x], got %v", d)
+	}
+	if d := a.TakeDelta(); d.Len() != 0 || d.String() != "[]" {
+		t.Errorf("Expected an empty delta, got %v", d)
+	}
+	checkMergeLaws(t, newGSet, gSetOp)
+	checkConvergence(t, newGSet, gSetOp)
+	checkDeltaConvergence(t, newGSet, gSetOp)
+}
+
+func TestTwoPhaseSet(t *testing.T) {
+	a, b := NewTwoPhaseSet[string](), NewTwoPhaseSet[string]()
+	a.Add("x")
+	b.Merge(a)
+	if !b.Remove("x") || b.Remove("x") || b.Remove("missing") {
+		t.Error("Expected only the first removal of x to succeed")
+	}
+	if b.Add("x") {
+		t.Error("TwoPhaseSet.Add() returned true for a removed element")
+	}
+	// the removal wins over a concurrent add
+	a.Add("x")
+	a.Add("y")
+	a.Merge(b.TakeDelta())
+	if a.Contains("x") || a.Len() != 1 || len(a.ToSlice()) != 1 {
+		t.Errorf("Expected [y], got %v", a)
+	}
+	if a.String() != "[y]" {
+		t.Errorf("Expected [y], got %s", a)
+	}
+	checkMergeLaws(t, newTwoPhaseSet, twoPhaseSetOp)
+	checkConvergence(t, newTwoPhaseSet, twoPhaseSetOp)
+	checkDeltaConvergence(t, newTwoPhaseSet, twoPhaseSetOp)
+}
+
+func TestORSet(t *testing.T) {
+	a, b := NewORSet[string]("a"), NewORSet[string]("b")
+	if a.Replica() != "a" {
+		t.Errorf("Expected replica a, got %s", a.Replica())
+	}
+	a.Add("x")
+	b.Merge(a)
+	// b removes the add it saw while a adds x again concurrently
+	if !b.Remove("x") || b.Remove("x") {
+		t.Error("Expected only the first removal of x to succeed")
+	}
+	a.Add("x")
+	a.Merge(b)
+	b.Merge(a)
+	if !a.Contains("x") || !b.Contains("x") {
+		t.Errorf("Expected the concurrent add to win, got %v and %v", a, b)
+	}
+	// a removal that saw every add removes the element everywhere
+	b.Remove("x")
+	a.Merge(b.TakeDelta())
+	if a.Contains("x") || a.Len() != 0 || a.String() != "[]" {
+		t.Errorf("Expected an empty set, got %v", a)
+	}
+	// removed elements can be added again
+	a.Add("x")
+	b.Merge(a)
+	if !b.Contains("x") || len(b.ToSlice()) != 1 {
+		t.Errorf("Expected [x], got %v", b)
+	}
+	if (Tag{"a", 3}).String() != "a:3" {
+		t.Errorf("Expected a:3, got %s", Tag{"a", 3})
+	}
+	checkMergeLaws(t, newORSet, orSetOp)
+	checkConvergence(t, newORSet, orSetOp)
+	checkDeltaConvergence(t, newORSet, orSetOp)
+}
+
+func TestORSet_Restart(t *testing.T) {
+	a, b := NewORSet[int]("a"), NewORSet[int]("b")
+	a.Add(1)
+	b.Merge(a)
+	b.Remove(1)
+	// a restarts from an empty state and gets its old state back from b
+	a = NewORSet[int]("a")
+	a.Merge(b)
+	a.Add(1)
+	b.Merge(a)
+	if !b.Contains(1) {
+		t.Error("Expected the new add not to reuse the tag of the removed one")
+	}
+}
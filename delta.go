@@ -0,0 +1,146 @@
+package set
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+)
+
+// Delta is the change between two versions of a set
+// Applying it removes the Removed elements, then adds the Added ones.
+// A nil Added or Removed set is treated as empty.
+type Delta[T comparable] struct {
+	Added   *Set[T]
+	Removed *Set[T]
+}
+
+// deltaElements is the encoded form of a Delta
+type deltaElements[T comparable] struct {
+	Added   []T `json:"added"`
+	Removed []T `json:"removed"`
+}
+
+// Diff returns the delta that turns before into after
+func Diff[T comparable](before, after *Set[T]) *Delta[T] {
+	return &Delta[T]{
+		Added:   after.Difference(before),
+		Removed: before.Difference(after),
+	}
+}
+
+// ThreadSafeDiff returns the delta that turns before into after
+// Both sets are locked for the whole operation
+func ThreadSafeDiff[T comparable](before, after *ThreadSafeSet[T]) *Delta[T] {
+	unlock := lockAll(before, after)
+	defer unlock()
+	return &Delta[T]{
+		Added:   &Set[T]{m: differenceMaps(after.m, []map[T]struct{}{before.m})},
+		Removed: &Set[T]{m: differenceMaps(before.m, []map[T]struct{}{after.m})},
+	}
+}
+
+// Apply removes the removed elements of the delta from the set, then adds the added ones
+func (s *Set[T]) Apply(d *Delta[T]) {
+	for k := range d.removed().m {
+		s.Remove(k)
+	}
+	for k := range d.added().m {
+		s.Add(k)
+	}
+}
+
+// Apply removes the removed elements of the delta from the set, then adds the added ones, as one operation
+func (s *ThreadSafeSet[T]) Apply(d *Delta[T]) {
+	s.l.Lock()
+	defer s.l.Unlock()
+	for k := range d.removed().m {
+		s.delete(k)
+	}
+	for k := range d.added().m {
+		s.insert(k)
+	}
+}
+
+// Then returns a delta with the effect of applying d and then d2
+// An element added by one and removed by the other cancels out
+func (d *Delta[T]) Then(d2 *Delta[T]) *Delta[T] {
+	a1, r1, a2, r2 := d.added(), d.removed(), d2.added(), d2.removed()
+	return &Delta[T]{
+		Added:   Union(a1.Difference(r2), a2.Difference(r1)),
+		Removed: Union(r1.Difference(a2), r2.Difference(a1)),
+	}
+}
+
+// Invert returns the delta that undoes d
+func (d *Delta[T]) Invert() *Delta[T] {
+	return &Delta[T]{
+		Added:   d.removed().Copy(),
+		Removed: d.added().Copy(),
+	}
+}
+
+// IsEmpty returns true if the delta adds and removes nothing
+func (d *Delta[T]) IsEmpty() bool {
+	return d.added().IsEmpty() && d.removed().IsEmpty()
+}
+
+// MarshalJSON encodes the delta as {"added": [...], "removed": [...]}
+func (d *Delta[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.elements())
+}
+
+// UnmarshalJSON decodes a delta encoded by MarshalJSON
+func (d *Delta[T]) UnmarshalJSON(data []byte) error {
+	var e deltaElements[T]
+	if err := json.Unmarshal(data, &e); err != nil {
+		return err
+	}
+	d.setElements(e)
+	return nil
+}
+
+// MarshalBinary encodes the delta with encoding/gob
+// Interface element types must be registered with gob.Register
+func (d *Delta[T]) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(d.elements()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary decodes a delta encoded by MarshalBinary
+func (d *Delta[T]) UnmarshalBinary(data []byte) error {
+	var e deltaElements[T]
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&e); err != nil {
+		return err
+	}
+	d.setElements(e)
+	return nil
+}
+
+func (d *Delta[T]) added() *Set[T] {
+	if d.Added == nil {
+		return NewSet[T]()
+	}
+	return d.Added
+}
+
+func (d *Delta[T]) removed() *Set[T] {
+	if d.Removed == nil {
+		return NewSet[T]()
+	}
+	return d.Removed
+}
+
+func (d *Delta[T]) elements() deltaElements[T] {
+	return deltaElements[T]{
+		Added:   d.added().ToSlice(),
+		Removed: d.removed().ToSlice(),
+	}
+}
+
+func (d *Delta[T]) setElements(e deltaElements[T]) {
+	d.Added = NewSetFromSlice(e.Added)
+	d.Removed = NewSetFromSlice(e.Removed)
+}
@@ -0,0 +1,12 @@
+//go:build go1.20
+
+package set
+
+import "testing"
+
+// interface types only satisfy comparable from Go 1.20
+func TestDelta_BinaryInterface(t *testing.T) {
+	if _, err := (&Delta[any]{Added: NewSetFromSlice([]any{struct{}{}})}).MarshalBinary(); err == nil {
+		t.Error("Expected an error for an unregistered interface type")
+	}
+}
@@ -0,0 +1,110 @@
+package set
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+
+	"github.com/drkennetz/set/settest"
+)
+
+func TestDiff(t *testing.T) {
+	before := NewSetFromSlice([]int{1, 2, 3})
+	after := NewSetFromSlice([]int{2, 3, 4, 5})
+	d := Diff(before, after)
+	settest.AssertEqual[int](t, d.Added, NewSetFromSlice([]int{4, 5}))
+	settest.AssertEqual[int](t, d.Removed, NewSetFromSlice([]int{1}))
+	patched := before.Copy()
+	patched.Apply(d)
+	settest.AssertEqual[int](t, patched, after)
+	patched.Apply(d.Invert())
+	settest.AssertEqual[int](t, patched, before)
+	if d.IsEmpty() || !Diff(before, before).IsEmpty() {
+		t.Error("Expected only the diff of equal sets to be empty")
+	}
+}
+
+// go test -race -run TestThreadSafeDiff .
+func TestThreadSafeDiff(t *testing.T) {
+	before := NewThreadSafeSetFromSlice([]int{1, 2, 3})
+	after := NewThreadSafeSetFromSlice([]int{3, 4})
+	d := ThreadSafeDiff(before, after)
+	settest.AssertEqual[int](t, d.Added, NewSetFromSlice([]int{4}))
+	settest.AssertEqual[int](t, d.Removed, NewSetFromSlice([]int{1, 2}))
+	before.Apply(d)
+	settest.AssertEqual[int](t, before, after)
+	if !ThreadSafeDiff(after, after).IsEmpty() {
+		t.Error("Expected the diff of a set with itself to be empty")
+	}
+}
+
+func TestDelta_Then(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	random := func() *Set[int] {
+		s := NewSet[int]()
+		for i := 0; i < 10; i++ {
+			if r.Intn(2) == 0 {
+				s.Add(i)
+			}
+		}
+		return s
+	}
+	for i := 0; i < 100; i++ {
+		a, b, c := random(), random(), random()
+		composed := Diff(a, b).Then(Diff(b, c))
+		settest.AssertEqual[int](t, composed.Added, Diff(a, c).Added)
+		settest.AssertEqual[int](t, composed.Removed, Diff(a, c).Removed)
+		patched := a.Copy()
+		patched.Apply(composed)
+		settest.AssertEqual[int](t, patched, c)
+	}
+}
+
+func TestDelta_ZeroValue(t *testing.T) {
+	var d Delta[string]
+	if !d.IsEmpty() {
+		t.Error("Expected the zero delta to be empty")
+	}
+	s := NewSetFromSlice([]string{"a"})
+	s.Apply(&d)
+	s.Apply(d.Invert())
+	s.Apply(d.Then(&Delta[string]{Added: NewSetFromSlice([]string{"b"})}))
+	settest.AssertEqual[string](t, s, NewSetFromSlice([]string{"a", "b"}))
+}
+
+func TestDelta_JSON(t *testing.T) {
+	d := Diff(NewSetFromSlice([]string{"a", "b"}), NewSetFromSlice([]string{"b", "c"}))
+	data, err := json.Marshal(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"added":["c"],"removed":["a"]}` {
+		t.Errorf("Expected {\"added\":[\"c\"],\"removed\":[\"a\"]}, got %s", data)
+	}
+	var decoded Delta[string]
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	settest.AssertEqual[string](t, decoded.Added, d.Added)
+	settest.AssertEqual[string](t, decoded.Removed, d.Removed)
+	if err := json.Unmarshal([]byte(`{"added": [1]}`), &decoded); err == nil {
+		t.Error("Expected an error for elements of the wrong type")
+	}
+}
+
+func TestDelta_Binary(t *testing.T) {
+	d := Diff(NewSetFromSlice([]int{1, 2, 3}), NewSetFromSlice([]int{3, 4}))
+	data, err := d.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Delta[int]
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	settest.AssertEqual[int](t, decoded.Added, d.Added)
+	settest.AssertEqual[int](t, decoded.Removed, d.Removed)
+	if err := decoded.UnmarshalBinary([]byte("garbage")); err == nil {
+		t.Error("Expected an error for invalid data")
+	}
+}
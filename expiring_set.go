@@ -0,0 +1,272 @@
+package set
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Clock tells an ExpiringSet the current time, tests can supply a fake one
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock is the Clock backed by time.Now
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// expiringConfig holds the options of an ExpiringSet
+type expiringConfig struct {
+	clock    Clock
+	interval time.Duration
+}
+
+// ExpiringOption configures an ExpiringSet
+type ExpiringOption func(*expiringConfig)
+
+// WithClock makes an ExpiringSet read the time from c instead of time.Now
+func WithClock(c Clock) ExpiringOption {
+	return func(cfg *expiringConfig) {
+		cfg.clock = c
+	}
+}
+
+// WithCleanupInterval makes an ExpiringSet remove expired elements in the background every interval
+// Without it expired elements are only removed when they are looked at, or by DeleteExpired
+func WithCleanupInterval(interval time.Duration) ExpiringOption {
+	return func(cfg *expiringConfig) {
+		cfg.interval = interval
+	}
+}
+
+// ExpiringSet is a thread-safe set whose elements expire after a time-to-live.
+// Expired elements are never reported by Contains, Len or ToSlice; they are
+// removed lazily when they are looked at, and optionally in the background.
+type ExpiringSet[T comparable] struct {
+	m        map[T]time.Time // the time each element expires at, the zero time never expires
+	l        sync.Mutex
+	ttl      time.Duration
+	clock    Clock
+	onExpire func(T)
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewExpiringSet returns a new expiring set whose elements live for ttl unless another TTL is given
+// A ttl of zero or less means elements don't expire
+// If WithCleanupInterval is given, Close must be called to stop the background cleanup
+func NewExpiringSet[T comparable](ttl time.Duration, opts ...ExpiringOption) *ExpiringSet[T] {
+	cfg := expiringConfig{clock: systemClock{}}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	s := &ExpiringSet[T]{
+		m:     make(map[T]time.Time),
+		ttl:   ttl,
+		clock: cfg.clock,
+		stop:  make(chan struct{}),
+	}
+	if cfg.interval > 0 {
+		go s.cleanup(cfg.interval)
+	}
+	return s
+}
+
+// OnExpire sets a function that is called with every element that expires
+// It is called without the set's lock held, by whichever goroutine noticed the expiry
+func (s *ExpiringSet[T]) OnExpire(fn func(T)) {
+	s.l.Lock()
+	defer s.l.Unlock()
+	s.onExpire = fn
+}
+
+// Add adds an element to the set with the default TTL, refreshing it if it is already in the set
+func (s *ExpiringSet[T]) Add(e T) {
+	s.AddWithTTL(e, s.ttl)
+}
+
+// AddWithTTL adds an element to the set that expires after ttl, refreshing it if it is already in the set
+// A ttl of zero or less means the element doesn't expire
+func (s *ExpiringSet[T]) AddWithTTL(e T, ttl time.Duration) {
+	now := s.clock.Now()
+	s.l.Lock()
+	// an element that expired but wasn't swept yet is reported before it is added again
+	expired, _ := s.lookup(e, now)
+	s.m[e] = s.deadline(ttl)
+	fn := s.onExpire
+	s.l.Unlock()
+	s.notify(fn, expired)
+}
+
+// AddIfAbsent adds an element to the set with the default TTL and returns true if it was not in the set
+// An element that is in the set keeps its TTL
+func (s *ExpiringSet[T]) AddIfAbsent(e T) bool {
+	now := s.clock.Now()
+	s.l.Lock()
+	expired, present := s.lookup(e, now)
+	if !present {
+		s.m[e] = s.deadline(s.ttl)
+	}
+	fn := s.onExpire
+	s.l.Unlock()
+	s.notify(fn, expired)
+	return !present
+}
+
+// Touch resets the TTL of an element to the default and returns true if it was in the set
+func (s *ExpiringSet[T]) Touch(e T) bool {
+	now := s.clock.Now()
+	s.l.Lock()
+	expired, present := s.lookup(e, now)
+	if present {
+		s.m[e] = s.deadline(s.ttl)
+	}
+	fn := s.onExpire
+	s.l.Unlock()
+	s.notify(fn, expired)
+	return present
+}
+
+// Contains returns true if the set contains the element and it has not expired
+func (s *ExpiringSet[T]) Contains(e T) bool {
+	now := s.clock.Now()
+	s.l.Lock()
+	expired, present := s.lookup(e, now)
+	fn := s.onExpire
+	s.l.Unlock()
+	s.notify(fn, expired)
+	return present
+}
+
+// TTL returns the time an element has left to live and true, or false if it is not in the set
+// Elements that don't expire have a TTL of zero
+func (s *ExpiringSet[T]) TTL(e T) (time.Duration, bool) {
+	now := s.clock.Now()
+	s.l.Lock()
+	expired, present := s.lookup(e, now)
+	deadline := s.m[e]
+	fn := s.onExpire
+	s.l.Unlock()
+	s.notify(fn, expired)
+	if !present || deadline.IsZero() {
+		return 0, present
+	}
+	return deadline.Sub(now), true
+}
+
+// Remove removes an element from the set without calling the expiry function
+func (s *ExpiringSet[T]) Remove(e T) {
+	s.l.Lock()
+	defer s.l.Unlock()
+	delete(s.m, e)
+}
+
+// Len returns the number of elements in the set that have not expired
+func (s *ExpiringSet[T]) Len() int {
+	s.DeleteExpired()
+	s.l.Lock()
+	defer s.l.Unlock()
+	return len(s.m)
+}
+
+// IsEmpty returns true if every element in the set has expired
+func (s *ExpiringSet[T]) IsEmpty() bool {
+	return s.Len() == 0
+}
+
+// Clear removes all elements from the set without calling the expiry function
+func (s *ExpiringSet[T]) Clear() {
+	s.l.Lock()
+	defer s.l.Unlock()
+	s.m = make(map[T]time.Time)
+}
+
+// ToSlice returns a slice of the elements in the set that have not expired
+func (s *ExpiringSet[T]) ToSlice() []T {
+	s.DeleteExpired()
+	s.l.Lock()
+	defer s.l.Unlock()
+	slice := make([]T, 0, len(s.m))
+	for k := range s.m {
+		slice = append(slice, k)
+	}
+	return slice
+}
+
+// String returns a string representation of the elements that have not expired
+func (s *ExpiringSet[T]) String() string {
+	return fmt.Sprintf("%v", s.ToSlice())
+}
+
+// DeleteExpired removes every expired element, calls the expiry function with them and returns how many there were
+func (s *ExpiringSet[T]) DeleteExpired() int {
+	now := s.clock.Now()
+	s.l.Lock()
+	var expired []T
+	for k, deadline := range s.m {
+		if !deadline.IsZero() && !now.Before(deadline) {
+			delete(s.m, k)
+			expired = append(expired, k)
+		}
+	}
+	fn := s.onExpire
+	s.l.Unlock()
+	s.notify(fn, expired)
+	return len(expired)
+}
+
+// Close stops the background cleanup, it is safe to call more than once
+func (s *ExpiringSet[T]) Close() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+}
+
+// deadline returns the time an element added now with ttl expires at
+func (s *ExpiringSet[T]) deadline(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return s.clock.Now().Add(ttl)
+}
+
+// lookup returns whether the element is in the set at now, removing it if it has expired
+// It returns the removed element so the caller can notify once the lock is released
+func (s *ExpiringSet[T]) lookup(e T, now time.Time) (expired []T, present bool) {
+	deadline, ok := s.m[e]
+	if !ok {
+		return nil, false
+	}
+	if !deadline.IsZero() && !now.Before(deadline) {
+		delete(s.m, e)
+		return []T{e}, false
+	}
+	return nil, true
+}
+
+// notify calls fn with every expired element
+func (s *ExpiringSet[T]) notify(fn func(T), expired []T) {
+	if fn == nil {
+		return
+	}
+	for _, e := range expired {
+		fn(e)
+	}
+}
+
+// cleanup removes expired elements every interval until the set is closed
+func (s *ExpiringSet[T]) cleanup(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.DeleteExpired()
+		case <-s.stop:
+			return
+		}
+	}
+}
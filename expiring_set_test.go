@@ -0,0 +1,167 @@
+package set
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/drkennetz/set/settest"
+)
+
+// fakeClock is a Clock that only moves when told to
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestExpiringSet_Expiry(t *testing.T) {
+	clock := newFakeClock()
+	s := NewExpiringSet[string](time.Minute, WithClock(clock))
+	var expired []string
+	s.OnExpire(func(e string) {
+		expired = append(expired, e)
+	})
+	s.Add("a")
+	s.AddWithTTL("b", 3*time.Minute)
+	s.AddWithTTL("forever", 0)
+	if !s.Contains("a") || s.Len() != 3 {
+		t.Errorf("Expected 3 live elements, got %v", s)
+	}
+	clock.Advance(time.Minute)
+	if s.Contains("a") {
+		t.Error("ExpiringSet.Contains() returned true for an expired element")
+	}
+	if len(expired) != 1 || expired[0] != "a" {
+		t.Errorf("Expected a to be reported as expired, got %v", expired)
+	}
+	if ttl, ok := s.TTL("b"); !ok || ttl != 2*time.Minute {
+		t.Errorf("Expected b to have 2m left, got %v %v", ttl, ok)
+	}
+	if ttl, ok := s.TTL("forever"); !ok || ttl != 0 {
+		t.Errorf("Expected forever to have no TTL, got %v %v", ttl, ok)
+	}
+	clock.Advance(time.Hour)
+	if _, ok := s.TTL("b"); ok {
+		t.Error("ExpiringSet.TTL() returned true for an expired element")
+	}
+	settest.AssertEqual[string](t, s, NewSetFromSlice([]string{"forever"}))
+	if len(expired) != 2 || expired[1] != "b" {
+		t.Errorf("Expected b to be reported as expired, got %v", expired)
+	}
+}
+
+func TestExpiringSet_AddExpired(t *testing.T) {
+	clock := newFakeClock()
+	s := NewExpiringSet[string](time.Minute, WithClock(clock))
+	var expired []string
+	s.OnExpire(func(e string) {
+		expired = append(expired, e)
+	})
+	s.Add("a")
+	s.Add("a")
+	clock.Advance(time.Minute)
+	// adding again must report the expiry that no lookup or sweep saw
+	s.Add("a")
+	if len(expired) != 1 || expired[0] != "a" {
+		t.Errorf("Expected a to be reported as expired once, got %v", expired)
+	}
+	if !s.Contains("a") {
+		t.Error("Expected a to be added again after expiring")
+	}
+}
+
+func TestExpiringSet_Touch(t *testing.T) {
+	clock := newFakeClock()
+	s := NewExpiringSet[int](time.Minute, WithClock(clock))
+	s.Add(1)
+	clock.Advance(50 * time.Second)
+	if !s.Touch(1) {
+		t.Error("ExpiringSet.Touch() returned false for a live element")
+	}
+	clock.Advance(50 * time.Second)
+	if !s.Contains(1) {
+		t.Error("ExpiringSet.Touch() did not refresh the TTL")
+	}
+	clock.Advance(time.Minute)
+	if s.Touch(1) {
+		t.Error("ExpiringSet.Touch() returned true for an expired element")
+	}
+	if s.Touch(2) {
+		t.Error("ExpiringSet.Touch() returned true for a missing element")
+	}
+}
+
+func TestExpiringSet_AddIfAbsent(t *testing.T) {
+	clock := newFakeClock()
+	s := NewExpiringSet[string](time.Minute, WithClock(clock))
+	if !s.AddIfAbsent("delivery-1") {
+		t.Error("ExpiringSet.AddIfAbsent() returned false for a new element")
+	}
+	clock.Advance(30 * time.Second)
+	if s.AddIfAbsent("delivery-1") {
+		t.Error("ExpiringSet.AddIfAbsent() returned true for a live element")
+	}
+	// AddIfAbsent must not refresh the TTL of a live element
+	clock.Advance(30 * time.Second)
+	if !s.AddIfAbsent("delivery-1") {
+		t.Error("ExpiringSet.AddIfAbsent() returned false for an expired element")
+	}
+}
+
+func TestExpiringSet_DeleteExpired(t *testing.T) {
+	clock := newFakeClock()
+	s := NewExpiringSet[int](time.Second, WithClock(clock))
+	for i := 0; i < 10; i++ {
+		s.Add(i)
+	}
+	s.AddWithTTL(10, time.Hour)
+	clock.Advance(time.Second)
+	if n := s.DeleteExpired(); n != 10 {
+		t.Errorf("Expected 10 expired elements, got %d", n)
+	}
+	if s.IsEmpty() || s.Len() != 1 {
+		t.Errorf("Expected {10}, got %v", s)
+	}
+	s.Remove(10)
+	s.Add(11)
+	s.Clear()
+	if !s.IsEmpty() || s.String() != "[]" {
+		t.Errorf("Expected empty set, got %v", s)
+	}
+}
+
+// go test -race -run TestExpiringSet_Cleanup .
+func TestExpiringSet_Cleanup(t *testing.T) {
+	s := NewExpiringSet[int](time.Millisecond, WithCleanupInterval(time.Millisecond))
+	defer s.Close()
+	expired := make(chan int, 1)
+	s.OnExpire(func(e int) {
+		expired <- e
+	})
+	s.Add(1)
+	select {
+	case e := <-expired:
+		if e != 1 {
+			t.Errorf("Expected 1 to expire, got %d", e)
+		}
+	case <-time.After(time.Second):
+		t.Error("Expected the background cleanup to expire the element")
+	}
+	s.Close()
+}
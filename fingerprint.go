@@ -0,0 +1,235 @@
+package set
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/bits"
+	"reflect"
+)
+
+// Fingerprint is an order-independent 128-bit digest of the elements of a set.
+//
+// It is the sum modulo 2^128 of a hash of every element, the first 128 bits of the SHA-256 of the
+// seed and a canonical encoding of the element, so equal sets have equal fingerprints in any
+// process and adding or removing an element updates it in constant time.
+// Elements are encoded with their concrete type and by value, down through arrays, structs and
+// interfaces; pointers and channels are encoded by address, so a pointer, or a struct holding
+// one, is only fingerprinted consistently within a process.
+//
+// Two different sets whose elements nobody chose to collide have the same fingerprint with a
+// probability around 2^-128. A sum of hashes is not collision resistant against an adversary who
+// chooses elements, though: with enough candidate elements, a subset summing to a target can be
+// found far faster than 2^64 work. Use FingerprintSeed with a secret seed, or a cryptographic hash
+// of the sorted elements, when the elements come from an untrusted source.
+type Fingerprint [16]byte
+
+// String returns the fingerprint in hex
+func (f Fingerprint) String() string {
+	return hex.EncodeToString(f[:])
+}
+
+// fingerprintState is the running fingerprint of a set, kept up to date once it has been computed
+type fingerprintState struct {
+	valid  bool
+	hi, lo uint64
+}
+
+func (f *fingerprintState) add(hi, lo uint64) {
+	var carry uint64
+	f.lo, carry = bits.Add64(f.lo, lo, 0)
+	f.hi, _ = bits.Add64(f.hi, hi, carry)
+}
+
+func (f *fingerprintState) sub(hi, lo uint64) {
+	var borrow uint64
+	f.lo, borrow = bits.Sub64(f.lo, lo, 0)
+	f.hi, _ = bits.Sub64(f.hi, hi, borrow)
+}
+
+// reset sets the fingerprint to the one of the empty set, if it is being kept up to date
+func (f *fingerprintState) reset() {
+	f.hi, f.lo = 0, 0
+}
+
+func (f *fingerprintState) fingerprint() Fingerprint {
+	var fp Fingerprint
+	binary.BigEndian.PutUint64(fp[:8], f.hi)
+	binary.BigEndian.PutUint64(fp[8:], f.lo)
+	return fp
+}
+
+// Fingerprint returns the fingerprint of the set
+// The first call hashes every element; after that the fingerprint is kept up to date and reading it is O(1)
+func (s *Set[T]) Fingerprint() Fingerprint {
+	if !s.fp.valid {
+		s.fp = computeFingerprint(s.m, 0)
+	}
+	return s.fp.fingerprint()
+}
+
+// FingerprintSeed returns the fingerprint of the set hashed with the seed
+// It hashes every element on every call, only the unseeded Fingerprint is kept up to date
+func (s *Set[T]) FingerprintSeed(seed uint64) Fingerprint {
+	fp := computeFingerprint(s.m, seed)
+	return fp.fingerprint()
+}
+
+// Fingerprint returns the fingerprint of the set
+// The first call hashes every element; after that the fingerprint is kept up to date and reading it is O(1)
+func (s *ThreadSafeSet[T]) Fingerprint() Fingerprint {
+	s.l.Lock()
+	defer s.l.Unlock()
+	if !s.fp.valid {
+		s.fp = computeFingerprint(s.m, 0)
+	}
+	return s.fp.fingerprint()
+}
+
+// FingerprintSeed returns the fingerprint of the set hashed with the seed
+// It hashes every element on every call, only the unseeded Fingerprint is kept up to date
+func (s *ThreadSafeSet[T]) FingerprintSeed(seed uint64) Fingerprint {
+	s.l.Lock()
+	defer s.l.Unlock()
+	fp := computeFingerprint(s.m, seed)
+	return fp.fingerprint()
+}
+
+func computeFingerprint[T comparable](m map[T]struct{}, seed uint64) fingerprintState {
+	fp := fingerprintState{valid: true}
+	for k := range m {
+		fp.add(elementHash(seed, k))
+	}
+	return fp
+}
+
+// insertElem adds e to m, updating the fingerprint if it is being kept up to date
+func insertElem[T comparable](m map[T]struct{}, fp *fingerprintState, e T) {
+	if fp.valid {
+		if _, ok := m[e]; !ok {
+			fp.add(elementHash(0, e))
+		}
+	}
+	m[e] = struct{}{}
+}
+
+// deleteElem removes e from m, updating the fingerprint if it is being kept up to date
+func deleteElem[T comparable](m map[T]struct{}, fp *fingerprintState, e T) {
+	if fp.valid {
+		if _, ok := m[e]; ok {
+			fp.sub(elementHash(0, e))
+		}
+	}
+	delete(m, e)
+}
+
+// elementHash returns the first 128 bits of the SHA-256 of the seed and the canonical encoding of e
+func elementHash[T comparable](seed uint64, e T) (hi, lo uint64) {
+	buf := make([]byte, 8, 32)
+	binary.BigEndian.PutUint64(buf, seed)
+	buf = appendCanonical(buf, e)
+	sum := sha256.Sum256(buf)
+	return binary.BigEndian.Uint64(sum[:8]), binary.BigEndian.Uint64(sum[8:16])
+}
+
+// appendCanonical appends a tagged encoding of e that is the same in every process
+// The encoding starts with the concrete type, so in a Set[any] int(1) and int64(1) differ as they do as map keys
+func appendCanonical(buf []byte, e any) []byte {
+	// the common element types skip reflection, and encode like appendValue does
+	switch v := e.(type) {
+	case string:
+		return appendString(appendTypeName(buf, "string"), v)
+	case int:
+		return appendInt(appendTypeName(buf, "int"), int64(v))
+	case int64:
+		return appendInt(appendTypeName(buf, "int64"), v)
+	case uint64:
+		return appendUint(appendTypeName(buf, "uint64"), v)
+	case float64:
+		return appendFloat(append(appendTypeName(buf, "float64"), 'f'), v)
+	}
+	return appendInterface(buf, reflect.ValueOf(e))
+}
+
+// appendInterface appends the concrete type and the value held by an interface, v is invalid for nil
+func appendInterface(buf []byte, v reflect.Value) []byte {
+	if !v.IsValid() {
+		return append(buf, 'n')
+	}
+	t := v.Type()
+	name := t.String()
+	if t.Name() != "" && t.PkgPath() != "" {
+		// the package path tells apart types with the same name in different packages
+		name = t.PkgPath() + "." + t.Name()
+	}
+	return appendValue(appendTypeName(buf, name), v)
+}
+
+// appendValue appends the value of v by kind, going down through arrays, structs and interfaces
+// Like map keys, floats compare by value, so 0 and -0 encode the same wherever they are
+func appendValue(buf []byte, v reflect.Value) []byte {
+	switch v.Kind() {
+	case reflect.String:
+		return appendString(buf, v.String())
+	case reflect.Bool:
+		if v.Bool() {
+			return append(buf, 'b', 1)
+		}
+		return append(buf, 'b', 0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return appendInt(buf, v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return appendUint(buf, v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return appendFloat(append(buf, 'f'), v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		return appendFloat(appendFloat(append(buf, 'c'), real(c)), imag(c))
+	case reflect.Array:
+		buf = append(buf, 'a')
+		for i := 0; i < v.Len(); i++ {
+			buf = appendValue(buf, v.Index(i))
+		}
+		return buf
+	case reflect.Struct:
+		buf = append(buf, 'r')
+		for i := 0; i < v.NumField(); i++ {
+			buf = appendValue(buf, v.Field(i))
+		}
+		return buf
+	case reflect.Interface:
+		return appendInterface(buf, v.Elem())
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		// pointers compare by address, which only means something within a process
+		return binary.BigEndian.AppendUint64(append(buf, 'p'), uint64(v.Pointer()))
+	}
+	// the other kinds can't be map keys, so they never reach a set
+	return fmt.Appendf(append(buf, 'v'), "%#v", v)
+}
+
+func appendTypeName(buf []byte, name string) []byte {
+	return append(binary.AppendUvarint(append(buf, 't'), uint64(len(name))), name...)
+}
+
+func appendString(buf []byte, s string) []byte {
+	// the length keeps the strings of a struct or array apart
+	return append(binary.AppendUvarint(append(buf, 's'), uint64(len(s))), s...)
+}
+
+func appendInt(buf []byte, i int64) []byte {
+	return binary.BigEndian.AppendUint64(append(buf, 'i'), uint64(i))
+}
+
+func appendUint(buf []byte, u uint64) []byte {
+	return binary.BigEndian.AppendUint64(append(buf, 'u'), u)
+}
+
+func appendFloat(buf []byte, f float64) []byte {
+	// 0 and -0 are the same map key
+	if f == 0 {
+		f = 0
+	}
+	return binary.BigEndian.AppendUint64(buf, math.Float64bits(f))
+}
@@ -0,0 +1,32 @@
+//go:build go1.20
+
+package set
+
+import "testing"
+
+// interface types only satisfy comparable from Go 1.20
+func TestSet_FingerprintInterfaces(t *testing.T) {
+	elems := []any{"1", 1, int8(2), int16(3), int32(4), int64(5), uint(6), uint8(7), uint16(8), uint32(9), uint64(10),
+		uintptr(11), float32(12.5), 13.5, complex64(14), complex128(15), true, false, struct{ A int }{16}, [2]int{17, 18}}
+	seen := make(map[Fingerprint]any)
+	for _, e := range elems {
+		fp := NewSetFromSlice([]any{e}).Fingerprint()
+		if other, ok := seen[fp]; ok {
+			t.Errorf("Expected %#v and %#v to have different fingerprints", e, other)
+		}
+		seen[fp] = e
+	}
+	// different types are different map keys, even with the same value
+	for _, e := range []any{int8(1), int64(1), uint(1), 1.0, temperature(1), "1"} {
+		if NewSetFromSlice([]any{1}).Fingerprint() == NewSetFromSlice([]any{e}).Fingerprint() {
+			t.Errorf("Expected 1 and %#v to have different fingerprints", e)
+		}
+	}
+	type boxed struct{ V any }
+	if NewSetFromSlice([]boxed{{1}}).Fingerprint() == NewSetFromSlice([]boxed{{int8(1)}}).Fingerprint() {
+		t.Error("Expected the types held by interface fields to be told apart")
+	}
+	if NewSetFromSlice([]any{nil}).Fingerprint() == NewSet[any]().Fingerprint() {
+		t.Error("Expected {nil} to have a fingerprint")
+	}
+}
@@ -0,0 +1,189 @@
+package set
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestSet_Fingerprint(t *testing.T) {
+	a := NewSetFromSlice([]string{"x", "y", "z"})
+	b := NewSetFromSlice([]string{"z", "x", "y"})
+	if a.Fingerprint() != b.Fingerprint() {
+		t.Errorf("Expected equal sets to have equal fingerprints, got %s and %s", a.Fingerprint(), b.Fingerprint())
+	}
+	if a.Fingerprint() == NewSetFromSlice([]string{"x", "y"}).Fingerprint() {
+		t.Error("Expected different sets to have different fingerprints")
+	}
+	if NewSet[int]().Fingerprint() != (Fingerprint{}) {
+		t.Error("Expected the empty set to have the zero fingerprint")
+	}
+	// the fingerprint must not change between processes or releases
+	if got := NewSetFromSlice([]string{"a"}).Fingerprint().String(); got != "dd7035f158fdee0c72e9c9e6b028b7e6" {
+		t.Errorf("Unexpected fingerprint of {a}: %s", got)
+	}
+	if a.FingerprintSeed(1) == a.Fingerprint() || a.FingerprintSeed(1) != b.FingerprintSeed(1) {
+		t.Error("Expected the seed to change the fingerprint, the same way for equal sets")
+	}
+	if a.FingerprintSeed(0) != a.Fingerprint() {
+		t.Error("Expected the default seed to be zero")
+	}
+}
+
+func TestSet_FingerprintTypes(t *testing.T) {
+	if NewSetFromSlice([]float64{0}).Fingerprint() != NewSetFromSlice([]float64{math.Copysign(0, -1)}).Fingerprint() {
+		t.Error("Expected 0 and -0 to have the same fingerprint")
+	}
+	x, y := 1, 1
+	if NewSetFromSlice([]*int{&x}).Fingerprint() != NewSetFromSlice([]*int{&x}).Fingerprint() ||
+		NewSetFromSlice([]*int{&x}).Fingerprint() == NewSetFromSlice([]*int{&y}).Fingerprint() {
+		t.Error("Expected pointers to be fingerprinted by address")
+	}
+	// the common types skip reflection but must encode the same way
+	for _, e := range []any{"a", 1, int64(2), uint64(3), 4.5} {
+		if fast, slow := string(appendCanonical(nil, e)), string(appendInterface(nil, reflect.ValueOf(e))); fast != slow {
+			t.Errorf("Expected %#v to encode as %q, got %q", e, slow, fast)
+		}
+	}
+}
+
+// temperature is a named float type
+type temperature float64
+
+// point is a struct holding floats
+type point struct {
+	X, Y float64
+	tags [2]float32
+}
+
+func TestSet_FingerprintNegativeZero(t *testing.T) {
+	negZero := math.Copysign(0, -1)
+	p := NewSet[point]()
+	p.Fingerprint()
+	// -0 and 0 are the same map key in a field or array element, so the set ends up empty
+	p.Add(point{X: negZero, tags: [2]float32{float32(negZero)}})
+	p.Remove(point{})
+	if !p.IsEmpty() || p.Fingerprint() != (Fingerprint{}) {
+		t.Errorf("Expected an empty set with the zero fingerprint, got %v and %s", p, p.Fingerprint())
+	}
+	if NewSetFromSlice([]point{{}}).Fingerprint() != NewSetFromSlice([]point{{Y: negZero}}).Fingerprint() {
+		t.Error("Expected points with 0 and -0 to have the same fingerprint")
+	}
+	if NewSetFromSlice([]temperature{0}).Fingerprint() != NewSetFromSlice([]temperature{temperature(negZero)}).Fingerprint() {
+		t.Error("Expected a named float type to treat 0 and -0 the same")
+	}
+	if NewSetFromSlice([]point{{X: 1}}).Fingerprint() == NewSetFromSlice([]point{{Y: 1}}).Fingerprint() {
+		t.Error("Expected the fields of a struct to be told apart")
+	}
+	type names struct{ A, B string }
+	if NewSetFromSlice([]names{{"ab", ""}}).Fingerprint() == NewSetFromSlice([]names{{"a", "b"}}).Fingerprint() {
+		t.Error("Expected the strings of a struct to be told apart")
+	}
+}
+
+// checkFingerprint compares the running fingerprint with one computed from scratch
+func checkFingerprint(t *testing.T, name string, s *Set[int]) {
+	t.Helper()
+	if want := s.FingerprintSeed(0); s.Fingerprint() != want {
+		t.Errorf("%s: expected the fingerprint of %v to be %s, got %s", name, s, want, s.Fingerprint())
+	}
+}
+
+func TestSet_FingerprintIncremental(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	random := func() *Set[int] {
+		s := NewSet[int]()
+		for i := 0; i < 10; i++ {
+			s.Add(r.Intn(20))
+		}
+		return s
+	}
+	s := random()
+	s.Fingerprint()
+	ops := map[string]func(){
+		"Add":                     func() { s.Add(r.Intn(20)) },
+		"Remove":                  func() { s.Remove(r.Intn(20)) },
+		"Pop":                     func() { s.Pop() },
+		"TryPop":                  func() { s.TryPop() },
+		"UnionWith":               func() { s.UnionWith(random()) },
+		"IntersectWith":           func() { s.IntersectWith(random()) },
+		"DifferenceWith":          func() { s.DifferenceWith(random()) },
+		"SymmetricDifferenceWith": func() { s.SymmetricDifferenceWith(random()) },
+		"RemoveIf":                func() { s.RemoveIf(func(i int) bool { return i%3 == 0 }) },
+		"TryAdd":                  func() { s.TryAdd(r.Intn(20)) },
+		"Apply":                   func() { s.Apply(Diff(s, random())) },
+		"Clear":                   func() { s.Clear() },
+	}
+	for i := 0; i < 20; i++ {
+		for name, op := range ops {
+			op()
+			checkFingerprint(t, name, s)
+		}
+	}
+}
+
+// go test -race -run TestTSSFingerprint .
+func TestTSSFingerprint(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	random := func() *ThreadSafeSet[int] {
+		s := NewThreadSafeSet[int]()
+		for i := 0; i < 10; i++ {
+			s.Add(r.Intn(20))
+		}
+		return s
+	}
+	s, other := random(), random()
+	if s.FingerprintSeed(0) != s.Fingerprint() {
+		t.Error("Expected the default seed to be zero")
+	}
+	other.Fingerprint()
+	failed := errors.New("rolled back")
+	ops := map[string]func(){
+		"Add":                     func() { s.Add(r.Intn(20)) },
+		"Remove":                  func() { s.Remove(r.Intn(20)) },
+		"Pop":                     func() { s.Pop() },
+		"TryPop":                  func() { s.TryPop() },
+		"AddIfAbsent":             func() { s.AddIfAbsent(r.Intn(20)) },
+		"RemoveIfPresent":         func() { s.RemoveIfPresent(r.Intn(20)) },
+		"Move":                    func() { s.Move(r.Intn(20), other) },
+		"Replace":                 func() { s.Replace(r.Intn(20), r.Intn(20)) },
+		"Compute":                 func() { s.Compute(r.Intn(20), func(present bool) bool { return !present }) },
+		"UnionWith":               func() { s.UnionWith(random()) },
+		"IntersectWith":           func() { s.IntersectWith(random()) },
+		"DifferenceWith":          func() { s.DifferenceWith(random()) },
+		"SymmetricDifferenceWith": func() { s.SymmetricDifferenceWith(random()) },
+		"RemoveIf":                func() { s.RemoveIf(func(i int) bool { return i%3 == 0 }) },
+		"TryAdd":                  func() { s.TryAdd(r.Intn(20)) },
+		"Apply":                   func() { s.Apply(ThreadSafeDiff(s, random())) },
+		"Clear":                   func() { s.Clear() },
+		"Update": func() {
+			Update([]*ThreadSafeSet[int]{s}, func(tx *Tx[int]) error {
+				tx.Add(s, r.Intn(20))
+				tx.Remove(s, r.Intn(20))
+				return nil
+			})
+		},
+		"UpdateRollback": func() {
+			Update([]*ThreadSafeSet[int]{s}, func(tx *Tx[int]) error {
+				tx.Add(s, r.Intn(20))
+				tx.Remove(s, r.Intn(20))
+				return failed
+			})
+		},
+	}
+	check := func(name string, s *ThreadSafeSet[int]) {
+		t.Helper()
+		if want := s.FingerprintSeed(0); s.Fingerprint() != want {
+			t.Errorf("%s: expected the fingerprint of %v to be %s, got %s", name, s, want, s.Fingerprint())
+		}
+	}
+	for i := 0; i < 20; i++ {
+		for name, op := range ops {
+			op()
+			check(name, s)
+			check(name, other)
+		}
+	}
+}
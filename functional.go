@@ -0,0 +1,69 @@
+package set
+
+// MapTo returns a new set containing the results of applying the function to each element
+// Unlike Set.Map, the result can hold a different type than s
+func MapTo[T, U comparable](s *Set[T], f func(T) U) *Set[U] {
+	s2 := NewSet[U]()
+	for k := range s.m {
+		s2.m[f(k)] = struct{}{}
+	}
+	return s2
+}
+
+// FlatMap returns a new set containing every element of the slices returned by applying the function to each element
+func FlatMap[T, U comparable](s *Set[T], f func(T) []U) *Set[U] {
+	s2 := NewSet[U]()
+	for k := range s.m {
+		for _, v := range f(k) {
+			s2.m[v] = struct{}{}
+		}
+	}
+	return s2
+}
+
+// Fold applies the function to an accumulator starting at init and each element in the set, and returns the accumulator
+func Fold[T comparable, A any](s *Set[T], init A, f func(A, T) A) A {
+	acc := init
+	for k := range s.m {
+		acc = f(acc, k)
+	}
+	return acc
+}
+
+// ThreadSafeMapTo returns a new set containing the results of applying the function to each element
+// Unlike ThreadSafeSet.Map, the result can hold a different type than s
+func ThreadSafeMapTo[T, U comparable](s *ThreadSafeSet[T], fn func(T) U) *ThreadSafeSet[U] {
+	s.l.Lock()
+	defer s.l.Unlock()
+	// we don't lock s2 because it is created here
+	s2 := NewThreadSafeSet[U]()
+	for k := range s.m {
+		s2.m[fn(k)] = struct{}{}
+	}
+	return s2
+}
+
+// ThreadSafeFlatMap returns a new set containing every element of the slices returned by applying the function to each element
+func ThreadSafeFlatMap[T, U comparable](s *ThreadSafeSet[T], fn func(T) []U) *ThreadSafeSet[U] {
+	s.l.Lock()
+	defer s.l.Unlock()
+	// we don't lock s2 because it is created here
+	s2 := NewThreadSafeSet[U]()
+	for k := range s.m {
+		for _, v := range fn(k) {
+			s2.m[v] = struct{}{}
+		}
+	}
+	return s2
+}
+
+// ThreadSafeFold applies the function to an accumulator starting at init and each element in the set, and returns the accumulator
+func ThreadSafeFold[T comparable, A any](s *ThreadSafeSet[T], init A, fn func(A, T) A) A {
+	s.l.Lock()
+	defer s.l.Unlock()
+	acc := init
+	for k := range s.m {
+		acc = fn(acc, k)
+	}
+	return acc
+}
@@ -0,0 +1,71 @@
+package set
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/drkennetz/set/settest"
+)
+
+type user struct {
+	name  string
+	email string
+}
+
+func TestMapTo(t *testing.T) {
+	users := NewSetFromSlice([]user{{"a", "a@example.com"}, {"b", "b@example.com"}, {"c", "a@example.com"}})
+	emails := MapTo(users, func(u user) string { return u.email })
+	settest.AssertEqual[string](t, emails, NewSetFromSlice([]string{"a@example.com", "b@example.com"}))
+	tsEmails := ThreadSafeMapTo(NewThreadSafeSetFromSlice(users.ToSlice()), func(u user) string { return u.email })
+	settest.AssertEqual[string](t, tsEmails, emails)
+}
+
+func TestFlatMap(t *testing.T) {
+	words := NewSetFromSlice([]string{"a b", "b c", ""})
+	split := func(s string) []string { return strings.Fields(s) }
+	settest.AssertEqual[string](t, FlatMap(words, split), NewSetFromSlice([]string{"a", "b", "c"}))
+	settest.AssertEqual[string](t, ThreadSafeFlatMap(NewThreadSafeSetFromSlice(words.ToSlice()), split), NewSetFromSlice([]string{"a", "b", "c"}))
+}
+
+func TestFold(t *testing.T) {
+	words := NewSetFromSlice([]string{"cat", "horse", "ox"})
+	total := func(acc int, s string) int { return acc + len(s) }
+	if got := Fold(words, 100, total); got != 110 {
+		t.Errorf("Expected 110, got %d", got)
+	}
+	if got := ThreadSafeFold(NewThreadSafeSetFromSlice(words.ToSlice()), 0, total); got != 10 {
+		t.Errorf("Expected 10, got %d", got)
+	}
+	if got := Fold(NewSet[string](), "init", func(acc, s string) string { return acc + s }); got != "init" {
+		t.Errorf("Expected the initial value for an empty set, got %q", got)
+	}
+}
+
+func TestSet_TryReduce(t *testing.T) {
+	product := func(a, b int) int { return a * b }
+	if got, ok := NewSetFromSlice([]int{2, 3, 4}).TryReduce(product); !ok || got != 24 {
+		t.Errorf("Expected (24, true), got (%d, %v)", got, ok)
+	}
+	if got, ok := NewSetFromSlice([]int{7}).TryReduce(product); !ok || got != 7 {
+		t.Errorf("Expected (7, true), got (%d, %v)", got, ok)
+	}
+	if _, ok := NewSet[int]().TryReduce(product); ok {
+		t.Error("Expected false for an empty set")
+	}
+}
+
+// go test -race -run TestTSSTryReduce .
+func TestTSSTryReduce(t *testing.T) {
+	max := func(a, b int) int {
+		if a > b {
+			return a
+		}
+		return b
+	}
+	if got, ok := NewThreadSafeSetFromSlice([]int{-5, -2, -9}).TryReduce(max); !ok || got != -2 {
+		t.Errorf("Expected (-2, true), got (%d, %v)", got, ok)
+	}
+	if _, ok := NewThreadSafeSet[int]().TryReduce(max); ok {
+		t.Error("Expected false for an empty set")
+	}
+}
@@ -0,0 +1,111 @@
+package set
+
+// GroupBy splits the set into new sets keyed by the result of applying the function to each element
+func GroupBy[T comparable, K comparable](s *Set[T], keyFn func(T) K) map[K]*Set[T] {
+	return groupMaps(s.m, keyFn, s.derived)
+}
+
+// ThreadSafeGroupBy splits the set into new sets keyed by the result of applying the function to each element
+func ThreadSafeGroupBy[T comparable, K comparable](s *ThreadSafeSet[T], keyFn func(T) K) map[K]*ThreadSafeSet[T] {
+	s.l.Lock()
+	defer s.l.Unlock()
+	return groupMaps(s.m, keyFn, func(m map[T]struct{}) *ThreadSafeSet[T] {
+		return &ThreadSafeSet[T]{m: m}
+	})
+}
+
+// Partition returns the elements that satisfy the predicate and the rest as two new sets
+func (s *Set[T]) Partition(predicate func(T) bool) (*Set[T], *Set[T]) {
+	matching, rest := partitionMap(s.m, predicate)
+	return s.derived(matching), s.derived(rest)
+}
+
+// Chunk splits the set into new sets of at most n elements
+// The sizes of the chunks differ by at most one, and it panics if n is less than one
+func (s *Set[T]) Chunk(n int) []*Set[T] {
+	chunks := chunkMap(s.m, n)
+	sets := make([]*Set[T], len(chunks))
+	for i, m := range chunks {
+		sets[i] = s.derived(m)
+	}
+	return sets
+}
+
+// Partition returns the elements that pass the predicate and the rest as two new sets
+func (s *ThreadSafeSet[T]) Partition(predicate func(T) bool) (*ThreadSafeSet[T], *ThreadSafeSet[T]) {
+	s.l.Lock()
+	defer s.l.Unlock()
+	matching, rest := partitionMap(s.m, predicate)
+	return &ThreadSafeSet[T]{m: matching}, &ThreadSafeSet[T]{m: rest}
+}
+
+// Chunk splits the set into new sets of at most n elements
+// The sizes of the chunks differ by at most one, and it panics if n is less than one
+func (s *ThreadSafeSet[T]) Chunk(n int) []*ThreadSafeSet[T] {
+	s.l.Lock()
+	defer s.l.Unlock()
+	chunks := chunkMap(s.m, n)
+	sets := make([]*ThreadSafeSet[T], len(chunks))
+	for i, m := range chunks {
+		sets[i] = &ThreadSafeSet[T]{m: m}
+	}
+	return sets
+}
+
+func groupMaps[T, K comparable, S any](m map[T]struct{}, keyFn func(T) K, wrap func(map[T]struct{}) S) map[K]S {
+	groups := make(map[K]map[T]struct{})
+	for k := range m {
+		key := keyFn(k)
+		group, ok := groups[key]
+		if !ok {
+			group = make(map[T]struct{})
+			groups[key] = group
+		}
+		group[k] = struct{}{}
+	}
+	sets := make(map[K]S, len(groups))
+	for key, group := range groups {
+		sets[key] = wrap(group)
+	}
+	return sets
+}
+
+func partitionMap[T comparable](m map[T]struct{}, predicate func(T) bool) (map[T]struct{}, map[T]struct{}) {
+	matching := make(map[T]struct{})
+	rest := make(map[T]struct{})
+	for k := range m {
+		if predicate(k) {
+			matching[k] = struct{}{}
+		} else {
+			rest[k] = struct{}{}
+		}
+	}
+	return matching, rest
+}
+
+// chunkMap splits m into ceil(len(m)/n) maps, the first len(m)%count of them one larger than the rest
+func chunkMap[T comparable](m map[T]struct{}, n int) []map[T]struct{} {
+	if n < 1 {
+		panic("set: chunk size must be at least one")
+	}
+	count := (len(m) + n - 1) / n
+	chunks := make([]map[T]struct{}, 0, count)
+	if count == 0 {
+		return chunks
+	}
+	size, extra := len(m)/count, len(m)%count
+	var chunk map[T]struct{}
+	want := 0
+	for k := range m {
+		if len(chunk) == want {
+			want = size
+			if len(chunks) < extra {
+				want++
+			}
+			chunk = make(map[T]struct{}, want)
+			chunks = append(chunks, chunk)
+		}
+		chunk[k] = struct{}{}
+	}
+	return chunks
+}
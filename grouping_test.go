@@ -0,0 +1,90 @@
+package set
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/drkennetz/set/settest"
+)
+
+func TestGroupBy(t *testing.T) {
+	emails := NewSetFromSlice([]string{"a@x.com", "b@y.com", "c@x.com"})
+	domain := func(e string) string { return e[strings.IndexByte(e, '@')+1:] }
+	groups := GroupBy(emails, domain)
+	if len(groups) != 2 {
+		t.Fatalf("Expected 2 groups, got %v", groups)
+	}
+	settest.AssertEqual[string](t, groups["x.com"], NewSetFromSlice([]string{"a@x.com", "c@x.com"}))
+	settest.AssertEqual[string](t, groups["y.com"], NewSetFromSlice([]string{"b@y.com"}))
+	tsGroups := ThreadSafeGroupBy(NewThreadSafeSetFromSlice(emails.ToSlice()), domain)
+	if len(tsGroups) != 2 {
+		t.Fatalf("Expected 2 groups, got %v", tsGroups)
+	}
+	settest.AssertEqual[string](t, tsGroups["x.com"], groups["x.com"])
+	if len(GroupBy(NewSet[string](), domain)) != 0 {
+		t.Error("Expected no groups for an empty set")
+	}
+}
+
+func TestSet_Partition(t *testing.T) {
+	even := func(i int) bool { return i%2 == 0 }
+	matching, rest := NewSetFromSlice([]int{1, 2, 3, 4, 5}).Partition(even)
+	settest.AssertEqual[int](t, matching, NewSetFromSlice([]int{2, 4}))
+	settest.AssertEqual[int](t, rest, NewSetFromSlice([]int{1, 3, 5}))
+}
+
+// go test -race -run TestTSSPartition .
+func TestTSSPartition(t *testing.T) {
+	even := func(i int) bool { return i%2 == 0 }
+	matching, rest := NewThreadSafeSetFromSlice([]int{1, 2, 3, 4, 5}).Partition(even)
+	settest.AssertEqual[int](t, matching, NewSetFromSlice([]int{2, 4}))
+	settest.AssertEqual[int](t, rest, NewSetFromSlice([]int{1, 3, 5}))
+}
+
+func TestSet_Chunk(t *testing.T) {
+	for _, tc := range []struct {
+		len, n int
+		sizes  []int
+	}{
+		{0, 3, nil},
+		{5, 5, []int{5}},
+		{10, 4, []int{4, 3, 3}},
+		{7, 2, []int{2, 2, 2, 1}},
+		{3, 10, []int{3}},
+	} {
+		s := rangeSet(0, tc.len)
+		chunks := s.Chunk(tc.n)
+		if len(chunks) != len(tc.sizes) {
+			t.Errorf("Chunk(%d) of %d elements: expected %d chunks, got %d", tc.n, tc.len, len(tc.sizes), len(chunks))
+			continue
+		}
+		union := NewSet[int]()
+		for i, c := range chunks {
+			if c.Len() != tc.sizes[i] {
+				t.Errorf("Chunk(%d) of %d elements: expected chunk %d to have %d elements, got %d", tc.n, tc.len, i, tc.sizes[i], c.Len())
+			}
+			union.UnionWith(c)
+		}
+		settest.AssertEqual[int](t, union, s)
+	}
+}
+
+// go test -race -run TestTSSChunk .
+func TestTSSChunk(t *testing.T) {
+	s := NewThreadSafeSetFromSlice(rangeSet(0, 10).ToSlice())
+	chunks := s.Chunk(3)
+	if len(chunks) != 4 {
+		t.Fatalf("Expected 4 chunks, got %d", len(chunks))
+	}
+	union := ThreadSafeUnion(chunks...)
+	settest.AssertEqual[int](t, union, s)
+}
+
+func TestChunk_Panics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Chunk() did not panic for a chunk size of zero")
+		}
+	}()
+	NewSet[int]().Chunk(0)
+}
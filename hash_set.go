@@ -0,0 +1,381 @@
+package set
+
+import (
+	"fmt"
+	"hash/maphash"
+)
+
+// HashSet is a set of elements of any type, compared with user supplied hash and equality functions.
+// It holds the types a Set can't, like slices, maps, or structs compared by some of their fields.
+// Elements that are equal must have the same hash; the equality function decides between the
+// elements of a bucket that share a hash.
+// The binary operations expect both sets to use the same functions.
+type HashSet[T any] struct {
+	hash    func(T) uint64
+	equal   func(a, b T) bool
+	buckets map[uint64][]T
+	n       int
+}
+
+// NewHashSet returns a new hash set using the hash and equality functions
+func NewHashSet[T any](hash func(T) uint64, equal func(a, b T) bool) *HashSet[T] {
+	return &HashSet[T]{
+		hash:    hash,
+		equal:   equal,
+		buckets: make(map[uint64][]T),
+	}
+}
+
+// NewHashSetFromSlice returns a new hash set from a slice using the hash and equality functions
+func NewHashSetFromSlice[T any](hash func(T) uint64, equal func(a, b T) bool, s []T) *HashSet[T] {
+	set := NewHashSet(hash, equal)
+	for _, v := range s {
+		set.Add(v)
+	}
+	return set
+}
+
+// hashSeed seeds the hash helpers, so their hashes are only stable within a process
+var hashSeed = maphash.MakeSeed()
+
+// HashString returns a hash of the string for use with NewHashSet
+func HashString(s string) uint64 {
+	return maphash.String(hashSeed, s)
+}
+
+// HashBytes returns a hash of the bytes for use with NewHashSet
+func HashBytes(b []byte) uint64 {
+	return maphash.Bytes(hashSeed, b)
+}
+
+// CombineHashes returns a hash of the hashes in order, to hash a value made of several fields
+func CombineHashes(hashes ...uint64) uint64 {
+	var h maphash.Hash
+	h.SetSeed(hashSeed)
+	var buf [8]byte
+	for _, v := range hashes {
+		for i := range buf {
+			buf[i] = byte(v >> (8 * i))
+		}
+		h.Write(buf[:])
+	}
+	return h.Sum64()
+}
+
+// Add adds an element to the set
+func (s *HashSet[T]) Add(e T) {
+	h := s.hash(e)
+	if s.indexIn(s.buckets[h], e) < 0 {
+		s.buckets[h] = append(s.buckets[h], e)
+		s.n++
+	}
+}
+
+// Contains returns true if the set contains the element
+func (s *HashSet[T]) Contains(e T) bool {
+	return s.indexIn(s.buckets[s.hash(e)], e) >= 0
+}
+
+// Remove removes an element from the set
+func (s *HashSet[T]) Remove(e T) {
+	h := s.hash(e)
+	bucket := s.buckets[h]
+	i := s.indexIn(bucket, e)
+	if i < 0 {
+		return
+	}
+	s.n--
+	if len(bucket) == 1 {
+		delete(s.buckets, h)
+		return
+	}
+	// move the last element into the hole
+	last := len(bucket) - 1
+	bucket[i] = bucket[last]
+	var zero T
+	bucket[last] = zero
+	s.buckets[h] = bucket[:last]
+}
+
+// Pop removes and returns an arbitrary element from the set or returns the zero value of T if the set is empty
+func (s *HashSet[T]) Pop() T {
+	e, _ := s.TryPop()
+	return e
+}
+
+// TryPop removes and returns an arbitrary element from the set and true, or the zero value of T and false if the set is empty
+func (s *HashSet[T]) TryPop() (T, bool) {
+	var zero T
+	for _, bucket := range s.buckets {
+		e := bucket[len(bucket)-1]
+		s.Remove(e)
+		return e, true
+	}
+	return zero, false
+}
+
+// Intersection returns the intersection of two sets as a new set IE the values that are in both sets
+func (s *HashSet[T]) Intersection(s2 *HashSet[T]) *HashSet[T] {
+	s3 := s.empty()
+	// make sure s is the smaller set
+	if s.n > s2.n {
+		s, s2 = s2, s
+	}
+	s.each(func(e T) {
+		if s2.Contains(e) {
+			s3.Add(e)
+		}
+	})
+	return s3
+}
+
+// Union returns the union of two sets as a new set IE the values that are in either set without duplicates
+func (s *HashSet[T]) Union(s2 *HashSet[T]) *HashSet[T] {
+	s3 := s.Copy()
+	s3.UnionWith(s2)
+	return s3
+}
+
+// Difference returns the values in s that are not in s2 as a new set
+func (s *HashSet[T]) Difference(s2 *HashSet[T]) *HashSet[T] {
+	s3 := s.empty()
+	s.each(func(e T) {
+		if !s2.Contains(e) {
+			s3.Add(e)
+		}
+	})
+	return s3
+}
+
+// SymmetricDifference returns the values that are in one of the sets, but not both
+func (s *HashSet[T]) SymmetricDifference(s2 *HashSet[T]) *HashSet[T] {
+	s3 := s.Difference(s2)
+	s2.each(func(e T) {
+		if !s.Contains(e) {
+			s3.Add(e)
+		}
+	})
+	return s3
+}
+
+// UnionWith adds the values in s2 to s in place
+func (s *HashSet[T]) UnionWith(s2 *HashSet[T]) {
+	if s == s2 {
+		return
+	}
+	s2.each(s.Add)
+}
+
+// IntersectWith removes the values in s that are not in s2 in place
+func (s *HashSet[T]) IntersectWith(s2 *HashSet[T]) {
+	s.RemoveIf(func(e T) bool {
+		return !s2.Contains(e)
+	})
+}
+
+// DifferenceWith removes the values in s2 from s in place
+func (s *HashSet[T]) DifferenceWith(s2 *HashSet[T]) {
+	if s == s2 {
+		s.Clear()
+		return
+	}
+	s2.each(s.Remove)
+}
+
+// SymmetricDifferenceWith keeps the values that are in one of the sets, but not both, in s in place
+func (s *HashSet[T]) SymmetricDifferenceWith(s2 *HashSet[T]) {
+	if s == s2 {
+		s.Clear()
+		return
+	}
+	s2.each(func(e T) {
+		if s.Contains(e) {
+			s.Remove(e)
+		} else {
+			s.Add(e)
+		}
+	})
+}
+
+// RemoveIf removes the elements that satisfy the predicate in place and returns how many were removed
+func (s *HashSet[T]) RemoveIf(predicate func(T) bool) int {
+	removed := 0
+	for h, bucket := range s.buckets {
+		kept := bucket[:0]
+		for _, e := range bucket {
+			if !predicate(e) {
+				kept = append(kept, e)
+			}
+		}
+		removed += len(bucket) - len(kept)
+		if len(kept) == 0 {
+			delete(s.buckets, h)
+			continue
+		}
+		// clear the tail so removed elements can be collected
+		var zero T
+		for i := len(kept); i < len(bucket); i++ {
+			bucket[i] = zero
+		}
+		s.buckets[h] = kept
+	}
+	s.n -= removed
+	return removed
+}
+
+// RetainIf removes the elements that don't satisfy the predicate in place and returns how many were removed
+func (s *HashSet[T]) RetainIf(predicate func(T) bool) int {
+	return s.RemoveIf(func(e T) bool {
+		return !predicate(e)
+	})
+}
+
+// IsSubset returns true if s is a subset of s2 IE all values in s are in s2
+func (s *HashSet[T]) IsSubset(s2 *HashSet[T]) bool {
+	return s.All(s2.Contains)
+}
+
+// IsSuperset returns true if s is a superset of s2 IE all values in s2 are in s
+func (s *HashSet[T]) IsSuperset(s2 *HashSet[T]) bool {
+	return s2.IsSubset(s)
+}
+
+// IsDisjoint returns true if s and s2 have no common values IE their intersection is empty
+func (s *HashSet[T]) IsDisjoint(s2 *HashSet[T]) bool {
+	if s.n > s2.n {
+		s, s2 = s2, s
+	}
+	return !s.Any(s2.Contains)
+}
+
+// IsEqual returns true if s and s2 contain the same values
+func (s *HashSet[T]) IsEqual(s2 *HashSet[T]) bool {
+	return s.n == s2.n && s.IsSubset(s2)
+}
+
+// Copy returns a copy of the set
+func (s *HashSet[T]) Copy() *HashSet[T] {
+	s2 := s.empty()
+	for h, bucket := range s.buckets {
+		s2.buckets[h] = append([]T(nil), bucket...)
+	}
+	s2.n = s.n
+	return s2
+}
+
+// Len returns the number of elements in the set
+func (s *HashSet[T]) Len() int {
+	return s.n
+}
+
+// Clear removes all elements from the set
+func (s *HashSet[T]) Clear() {
+	s.buckets = make(map[uint64][]T)
+	s.n = 0
+}
+
+// IsEmpty returns true if the set is empty
+func (s *HashSet[T]) IsEmpty() bool {
+	return s.Len() == 0
+}
+
+// ToSlice returns a slice of the elements in the set
+func (s *HashSet[T]) ToSlice() []T {
+	slice := make([]T, 0, s.n)
+	for _, bucket := range s.buckets {
+		slice = append(slice, bucket...)
+	}
+	return slice
+}
+
+// Filter returns a new set containing only the elements that satisfy the predicate
+func (s *HashSet[T]) Filter(predicate func(T) bool) *HashSet[T] {
+	s2 := s.empty()
+	s.each(func(e T) {
+		if predicate(e) {
+			s2.Add(e)
+		}
+	})
+	return s2
+}
+
+// Map returns a new set containing the results of applying the function to each element
+func (s *HashSet[T]) Map(f func(T) T) *HashSet[T] {
+	s2 := s.empty()
+	s.each(func(e T) {
+		s2.Add(f(e))
+	})
+	return s2
+}
+
+// Reduce applies the function to each element in the set and returns the result
+func (s *HashSet[T]) Reduce(f func(T, T) T) T {
+	var result T
+	s.each(func(e T) {
+		result = f(result, e)
+	})
+	return result
+}
+
+// TryReduce applies the function to each element in the set, starting from an arbitrary element instead of the zero value of T
+// It returns the result and true, or the zero value of T and false if the set is empty
+func (s *HashSet[T]) TryReduce(f func(T, T) T) (T, bool) {
+	var result T
+	first := true
+	s.each(func(e T) {
+		if first {
+			result, first = e, false
+			return
+		}
+		result = f(result, e)
+	})
+	return result, !first
+}
+
+// Any returns true if any element in the set satisfies the predicate
+func (s *HashSet[T]) Any(predicate func(T) bool) bool {
+	for _, bucket := range s.buckets {
+		for _, e := range bucket {
+			if predicate(e) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// All returns true if all elements in the set satisfy the predicate
+func (s *HashSet[T]) All(predicate func(T) bool) bool {
+	return !s.Any(func(e T) bool {
+		return !predicate(e)
+	})
+}
+
+// String returns a string representation of the set
+func (s *HashSet[T]) String() string {
+	return fmt.Sprintf("%v", s.ToSlice())
+}
+
+// empty returns a new empty set with the same functions as s
+func (s *HashSet[T]) empty() *HashSet[T] {
+	return NewHashSet(s.hash, s.equal)
+}
+
+// indexIn returns the index of e in the bucket or -1
+func (s *HashSet[T]) indexIn(bucket []T, e T) int {
+	for i, v := range bucket {
+		if s.equal(v, e) {
+			return i
+		}
+	}
+	return -1
+}
+
+// each calls fn with every element, fn must not change s
+func (s *HashSet[T]) each(fn func(T)) {
+	for _, bucket := range s.buckets {
+		for _, e := range bucket {
+			fn(e)
+		}
+	}
+}
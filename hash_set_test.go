@@ -0,0 +1,130 @@
+package set
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/drkennetz/set/settest"
+)
+
+func hashInt(i int) uint64 {
+	return uint64(i)
+}
+
+// collidingHash puts every element in a few buckets to exercise the equality checks
+func collidingHash(i int) uint64 {
+	return uint64(i % 3)
+}
+
+func equalInt(a, b int) bool {
+	return a == b
+}
+
+func TestHashSet_Conformance(t *testing.T) {
+	settest.RunConformance(t, func() *HashSet[int] {
+		return NewHashSet(hashInt, equalInt)
+	})
+}
+
+func TestHashSet_ConformanceCollisions(t *testing.T) {
+	settest.RunConformance(t, func() *HashSet[int] {
+		return NewHashSet(collidingHash, equalInt)
+	})
+}
+
+// go test -fuzz FuzzHashSet .
+func FuzzHashSet(f *testing.F) {
+	settest.FuzzConformance(f, func() *HashSet[int] {
+		return NewHashSet(collidingHash, equalInt)
+	})
+}
+
+func TestHashSet_Slices(t *testing.T) {
+	s := NewHashSetFromSlice(HashBytes, bytes.Equal, [][]byte{[]byte("a"), []byte("b"), []byte("a")})
+	if s.Len() != 2 {
+		t.Errorf("Expected 2 elements, got %v", s)
+	}
+	if !s.Contains([]byte("b")) || s.Contains([]byte("c")) {
+		t.Errorf("Expected {a, b}, got %v", s)
+	}
+	s.Remove([]byte("a"))
+	s.Remove([]byte("missing"))
+	if s.Len() != 1 || s.Contains([]byte("a")) {
+		t.Errorf("Expected {b}, got %v", s)
+	}
+}
+
+type pkg struct {
+	name     string
+	version  string
+	metadata map[string]string
+}
+
+func TestHashSet_Fields(t *testing.T) {
+	hash := func(p pkg) uint64 {
+		return CombineHashes(HashString(p.name), HashString(p.version))
+	}
+	equal := func(a, b pkg) bool {
+		return a.name == b.name && a.version == b.version
+	}
+	s := NewHashSet(hash, equal)
+	s.Add(pkg{"set", "v1", map[string]string{"a": "b"}})
+	s.Add(pkg{"set", "v1", nil})
+	s.Add(pkg{"set", "v2", nil})
+	if s.Len() != 2 {
+		t.Errorf("Expected 2 elements, got %v", s)
+	}
+	if !s.Contains(pkg{name: "set", version: "v2"}) {
+		t.Error("Expected set v2 in the set")
+	}
+	if CombineHashes(1, 2) == CombineHashes(2, 1) {
+		t.Error("Expected CombineHashes to depend on the order of the hashes")
+	}
+}
+
+func TestHashSet_Methods(t *testing.T) {
+	s := NewHashSetFromSlice(collidingHash, equalInt, []int{1, 2, 3, 4, 5, 6})
+	if got, ok := s.TryReduce(func(a, b int) int { return a + b }); !ok || got != 21 {
+		t.Errorf("Expected (21, true), got (%d, %v)", got, ok)
+	}
+	if _, ok := NewHashSet(hashInt, equalInt).TryReduce(func(a, b int) int { return a + b }); ok {
+		t.Error("Expected false for an empty set")
+	}
+	if _, ok := NewHashSet(hashInt, equalInt).TryPop(); ok {
+		t.Error("Expected false for an empty set")
+	}
+	if n := s.RetainIf(func(i int) bool { return i > 2 }); n != 2 {
+		t.Errorf("Expected 2 removed elements, got %d", n)
+	}
+	settest.AssertEqual[int](t, s, NewSetFromSlice([]int{3, 4, 5, 6}))
+
+	other := NewHashSetFromSlice(collidingHash, equalInt, []int{5, 6, 7})
+	u := s.Copy()
+	u.UnionWith(other)
+	settest.AssertEqual[int](t, u, NewSetFromSlice([]int{3, 4, 5, 6, 7}))
+	i := s.Copy()
+	i.IntersectWith(other)
+	settest.AssertEqual[int](t, i, NewSetFromSlice([]int{5, 6}))
+	d := s.Copy()
+	d.DifferenceWith(other)
+	settest.AssertEqual[int](t, d, NewSetFromSlice([]int{3, 4}))
+	x := s.Copy()
+	x.SymmetricDifferenceWith(other)
+	settest.AssertEqual[int](t, x, NewSetFromSlice([]int{3, 4, 7}))
+
+	for _, op := range []func(*HashSet[int], *HashSet[int]){(*HashSet[int]).DifferenceWith, (*HashSet[int]).SymmetricDifferenceWith} {
+		x = s.Copy()
+		op(x, x)
+		if !x.IsEmpty() {
+			t.Errorf("Expected an empty set after an operation with itself, got %v", x)
+		}
+	}
+	x = s.Copy()
+	x.UnionWith(x)
+	x.IntersectWith(x)
+	settest.AssertEqual[int](t, x, s)
+	if !strings.HasPrefix(s.String(), "[") {
+		t.Errorf("Expected a slice representation, got %s", s)
+	}
+}
@@ -0,0 +1,144 @@
+package set
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrUnhashable is returned for an element whose dynamic value can't be a map key,
+// like a slice stored in an any, which would make a plain map write panic
+var ErrUnhashable = errors.New("set: unhashable element")
+
+// setConfig holds the options of NewSetWithOptions
+type setConfig struct {
+	checkHashable bool
+}
+
+// SetOption configures a Set created with NewSetWithOptions
+type SetOption func(*setConfig)
+
+// WithHashCheck makes Add, Contains, Remove, Map and ParallelMap check every element with reflection before it touches the map.
+// Unhashable elements are ignored instead of panicking: Add and Remove do nothing, Contains returns false,
+// and Map and ParallelMap leave the unhashable results out. Use TryAdd and TryContains to find out an element was unhashable.
+// The sets returned by the set's methods, like Copy, Union and Filter, and by GroupBy keep the check, and so do the
+// results of the Union, Intersection and Difference functions when one of their sets has it.
+func WithHashCheck() SetOption {
+	return func(cfg *setConfig) {
+		cfg.checkHashable = true
+	}
+}
+
+// NewSetWithOptions returns a new set configured by the options
+func NewSetWithOptions[T comparable](opts ...SetOption) *Set[T] {
+	var cfg setConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	s := NewSet[T]()
+	s.checkHashable = cfg.checkHashable
+	return s
+}
+
+// empty returns a new empty set with the options of s
+func (s *Set[T]) empty() *Set[T] {
+	return s.derived(make(map[T]struct{}))
+}
+
+// derived returns a new set of the elements of m with the options of s
+func (s *Set[T]) derived(m map[T]struct{}) *Set[T] {
+	return &Set[T]{m: m, checkHashable: s.checkHashable}
+}
+
+// TryAdd adds an element to the set, or returns an error wrapping ErrUnhashable if its dynamic value can't be stored
+func (s *Set[T]) TryAdd(e T) error {
+	if err := hashable(e); err != nil {
+		return err
+	}
+	s.insert(e)
+	return nil
+}
+
+// TryContains returns true if the set contains the element, or an error wrapping ErrUnhashable if its dynamic value can't be looked up
+func (s *Set[T]) TryContains(e T) (bool, error) {
+	if err := hashable(e); err != nil {
+		return false, err
+	}
+	_, ok := s.m[e]
+	return ok, nil
+}
+
+// TryAdd adds an element to the set, or returns an error wrapping ErrUnhashable if its dynamic value can't be stored
+func (s *ThreadSafeSet[T]) TryAdd(e T) error {
+	// check before locking, the reflection doesn't need the lock
+	if err := hashable(e); err != nil {
+		return err
+	}
+	s.l.Lock()
+	defer s.l.Unlock()
+	s.insert(e)
+	return nil
+}
+
+// TryContains returns true if the set contains the element, or an error wrapping ErrUnhashable if its dynamic value can't be looked up
+func (s *ThreadSafeSet[T]) TryContains(e T) (bool, error) {
+	if err := hashable(e); err != nil {
+		return false, err
+	}
+	s.l.Lock()
+	defer s.l.Unlock()
+	_, ok := s.m[e]
+	return ok, nil
+}
+
+// hashable returns an error wrapping ErrUnhashable if e can't be used as a map key
+func hashable[T comparable](e T) error {
+	v := reflect.ValueOf(&e).Elem()
+	if !mayHoldUnhashable(v.Type()) {
+		return nil
+	}
+	return hashableValue(v)
+}
+
+// mayHoldUnhashable returns true if a value of type t can hold an interface, the only place an unhashable value can hide in a comparable type
+func mayHoldUnhashable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Array:
+		return mayHoldUnhashable(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if mayHoldUnhashable(t.Field(i).Type) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hashableValue walks the interfaces, arrays and structs in v looking for a dynamic value that can't be hashed
+func hashableValue(v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return hashableValue(v.Elem())
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := hashableValue(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if err := hashableValue(v.Field(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Map, reflect.Func:
+		return fmt.Errorf("%w of type %s", ErrUnhashable, v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+//go:build go1.20
+
+// Set[any] and structs holding an interface only satisfy comparable from Go 1.20,
+// so the hash checks have nothing to test before it
+
+package set
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+type wrapper struct {
+	Name  string
+	Value any
+}
+
+func TestSet_TryAdd(t *testing.T) {
+	s := NewSet[any]()
+	for _, e := range []any{1, "a", nil, [2]any{1, "b"}, wrapper{"w", 2}} {
+		if err := s.TryAdd(e); err != nil {
+			t.Errorf("Set.TryAdd(%v) returned %v", e, err)
+		}
+	}
+	for _, e := range []any{[]int{1}, map[string]int{}, func() {}, [1]any{[]int{}}, wrapper{"w", []string{}}} {
+		if err := s.TryAdd(e); !errors.Is(err, ErrUnhashable) {
+			t.Errorf("Expected ErrUnhashable for %T, got %v", e, err)
+		}
+		if ok, err := s.TryContains(e); ok || !errors.Is(err, ErrUnhashable) {
+			t.Errorf("Expected (false, ErrUnhashable) for %T, got (%v, %v)", e, ok, err)
+		}
+	}
+	if s.Len() != 5 {
+		t.Errorf("Expected 5 elements, got %v", s)
+	}
+	if ok, err := s.TryContains(wrapper{"w", 2}); !ok || err != nil {
+		t.Errorf("Expected (true, nil), got (%v, %v)", ok, err)
+	}
+	if err := NewSet[int]().TryAdd(1); err != nil {
+		t.Errorf("Expected no error for an int, got %v", err)
+	}
+	if err := NewSet[any]().TryAdd([]int{1}); err == nil || err.Error() != "set: unhashable element of type []int" {
+		t.Errorf("Expected the type in the error, got %v", err)
+	}
+}
+
+// go test -race -run TestTSSTryAdd .
+func TestTSSTryAdd(t *testing.T) {
+	s := NewThreadSafeSet[any]()
+	if err := s.TryAdd("a"); err != nil {
+		t.Errorf("ThreadSafeSet.TryAdd() returned %v", err)
+	}
+	if err := s.TryAdd([]int{1}); !errors.Is(err, ErrUnhashable) {
+		t.Errorf("Expected ErrUnhashable, got %v", err)
+	}
+	if ok, err := s.TryContains("a"); !ok || err != nil {
+		t.Errorf("Expected (true, nil), got (%v, %v)", ok, err)
+	}
+	if _, err := s.TryContains(map[int]int{}); !errors.Is(err, ErrUnhashable) {
+		t.Errorf("Expected ErrUnhashable, got %v", err)
+	}
+}
+
+func TestNewSetWithOptions(t *testing.T) {
+	var decoded []any
+	if err := json.Unmarshal([]byte(`[1, "a", [1, 2], {"b": 3}, true, 1]`), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	s := NewSetWithOptions[any](WithHashCheck())
+	for _, e := range decoded {
+		s.Add(e)
+	}
+	if s.Len() != 3 {
+		t.Errorf("Expected the 3 hashable values, got %v", s)
+	}
+	if s.Contains([]any{1.0, 2.0}) {
+		t.Error("Set.Contains() returned true for an unhashable element")
+	}
+	s.Remove(map[string]any{})
+	mapped := s.Map(func(e any) any {
+		if e == true {
+			return []bool{true}
+		}
+		return e
+	})
+	if mapped.Len() != 2 {
+		t.Errorf("Expected Map to drop the unhashable result, got %v", mapped)
+	}
+	s.Remove("a")
+	if s.Len() != 2 || s.Contains("a") {
+		t.Errorf("Expected a to be removed, got %v", s)
+	}
+	// the sets derived from s keep the check
+	slice := []int{1}
+	for name, derived := range map[string]*Set[any]{
+		"Copy":         s.Copy(),
+		"Union":        s.Union(NewSet[any]()),
+		"Intersection": s.Intersection(NewSetFromSlice[any]([]any{1})),
+		"Difference":   s.Difference(NewSet[any]()),
+		"Filter":       s.Filter(func(any) bool { return true }),
+		"Map":          mapped,
+	} {
+		derived.Add(slice)
+		if derived.Contains(slice) {
+			t.Errorf("Expected the set from %s to ignore an unhashable element", name)
+		}
+	}
+	matching, _ := s.Partition(func(any) bool { return true })
+	if !matching.checkHashable || !s.Chunk(1)[0].checkHashable {
+		t.Error("Expected Partition and Chunk to keep the check")
+	}
+	for _, group := range GroupBy(s, func(e any) bool { return e == 1 }) {
+		if !group.checkHashable {
+			t.Error("Expected GroupBy to keep the check")
+		}
+	}
+	plain := NewSetFromSlice([]any{1})
+	if !Union(plain, s).checkHashable || !Intersection(plain, s).checkHashable || !Difference(s, plain).checkHashable {
+		t.Error("Expected Union, Intersection and Difference to keep the check")
+	}
+	if Union(plain).checkHashable || Difference(plain, s).checkHashable {
+		t.Error("Expected no check for sets built from plain sets")
+	}
+	if NewSetWithOptions[int]().checkHashable {
+		t.Error("Expected no check without WithHashCheck")
+	}
+}
+
+func TestSet_ParallelMapHashCheck(t *testing.T) {
+	s := NewSetWithOptions[any](WithHashCheck())
+	for i := 0; i < 100; i++ {
+		s.Add(i)
+	}
+	mapped, err := s.ParallelMap(context.Background(), func(e any) any {
+		if e.(int)%2 == 0 {
+			return []int{e.(int)}
+		}
+		return e
+	}, WithWorkers(4), WithSerialThreshold(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mapped.Len() != 50 || !mapped.checkHashable {
+		t.Errorf("Expected ParallelMap to drop the 50 unhashable results and keep the check, got %v", mapped)
+	}
+}
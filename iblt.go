@@ -0,0 +1,362 @@
+package set
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+)
+
+var (
+	// ErrElementTooLarge is returned for an encoded element longer than the sketch's maximum element size
+	ErrElementTooLarge = errors.New("set: element too large for sketch")
+	// ErrSketchMismatch is returned when sketches of different shapes are combined
+	ErrSketchMismatch = errors.New("set: sketches have different sizes")
+	// ErrSketchDecode is returned when a sketch holds too many differences to be decoded
+	ErrSketchDecode = errors.New("set: sketch could not be decoded")
+)
+
+// sketchHashes is the number of cells every element is added to, one in each subtable
+const sketchHashes = 3
+
+// sketchVersion is the first byte of an encoded sketch
+const sketchVersion = 1
+
+// sketchCell sums the elements that hash to it
+type sketchCell struct {
+	count int64
+	// hashSum is the xor of the checksums of the elements
+	hashSum uint64
+	// keySum is the xor of the elements, each prefixed by its length and padded to the maximum size
+	keySum []byte
+}
+
+// Sketch is an invertible Bloom lookup table of byte-encoded elements.
+// Subtracting the sketch of one set from the sketch of another cancels the elements they share,
+// and decoding the result lists the elements of the symmetric difference, as long as it is small
+// compared to the number of cells: around 1.5 cells per difference decode reliably.
+type Sketch struct {
+	maxSize int
+	cells   []sketchCell
+}
+
+// NewSketch returns a new sketch with at least the given number of cells, holding elements of at most maxElementSize bytes
+func NewSketch(cells, maxElementSize int) *Sketch {
+	if cells < sketchHashes {
+		cells = sketchHashes
+	}
+	// every subtable has the same number of cells
+	cells = (cells + sketchHashes - 1) / sketchHashes * sketchHashes
+	s := &Sketch{
+		maxSize: maxElementSize,
+		cells:   make([]sketchCell, cells),
+	}
+	// the key sums share one allocation
+	keySize := 4 + maxElementSize
+	keySums := make([]byte, cells*keySize)
+	for i := range s.cells {
+		s.cells[i].keySum = keySums[i*keySize : (i+1)*keySize : (i+1)*keySize]
+	}
+	return s
+}
+
+// Cells returns the number of cells in the sketch
+func (s *Sketch) Cells() int {
+	return len(s.cells)
+}
+
+// Insert adds an element to the sketch
+func (s *Sketch) Insert(e []byte) error {
+	return s.update(e, 1)
+}
+
+// Delete removes an element from the sketch
+func (s *Sketch) Delete(e []byte) error {
+	return s.update(e, -1)
+}
+
+// Subtract returns the sketch of the elements of s minus the elements of s2 as a new sketch
+func (s *Sketch) Subtract(s2 *Sketch) (*Sketch, error) {
+	if s.maxSize != s2.maxSize || len(s.cells) != len(s2.cells) {
+		return nil, ErrSketchMismatch
+	}
+	diff := NewSketch(len(s.cells), s.maxSize)
+	for i := range diff.cells {
+		c, c1, c2 := &diff.cells[i], &s.cells[i], &s2.cells[i]
+		c.count = c1.count - c2.count
+		c.hashSum = c1.hashSum ^ c2.hashSum
+		xorBytes(c.keySum, c1.keySum)
+		xorBytes(c.keySum, c2.keySum)
+	}
+	return diff, nil
+}
+
+// Decode lists the elements inserted more often than deleted, and the ones deleted more often than inserted
+// For a sketch returned by mine.Subtract(yours), they are the elements only in mine and the ones only in yours.
+// It returns ErrSketchDecode if the sketch holds too many elements to list; the sketch is not changed.
+func (s *Sketch) Decode() (inserted, deleted [][]byte, err error) {
+	work := s.clone()
+	queue := make([]int, 0, len(work.cells))
+	for i := range work.cells {
+		queue = append(queue, i)
+	}
+	// every element is peeled once, so a sketch that needs more peels than this was crafted to loop
+	peels := len(work.cells) * sketchHashes
+	for len(queue) > 0 {
+		i := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		e, ok := work.pure(i)
+		if !ok {
+			continue
+		}
+		if peels--; peels < 0 {
+			return nil, nil, ErrSketchDecode
+		}
+		count := work.cells[i].count
+		if count == 1 {
+			inserted = append(inserted, e)
+		} else {
+			deleted = append(deleted, e)
+		}
+		// peel the element off every cell it is in, which can make them pure
+		for _, j := range work.indices(e) {
+			work.apply(j, e, -count)
+			queue = append(queue, j)
+		}
+	}
+	for i := range work.cells {
+		if !work.cells[i].empty() {
+			return nil, nil, ErrSketchDecode
+		}
+	}
+	return inserted, deleted, nil
+}
+
+// MarshalBinary encodes the sketch
+func (s *Sketch) MarshalBinary() ([]byte, error) {
+	cellSize := 16 + 4 + s.maxSize
+	data := make([]byte, 9, 9+len(s.cells)*cellSize)
+	data[0] = sketchVersion
+	binary.BigEndian.PutUint32(data[1:], uint32(s.maxSize))
+	binary.BigEndian.PutUint32(data[5:], uint32(len(s.cells)))
+	for _, c := range s.cells {
+		data = binary.BigEndian.AppendUint64(data, uint64(c.count))
+		data = binary.BigEndian.AppendUint64(data, c.hashSum)
+		data = append(data, c.keySum...)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary decodes a sketch encoded by MarshalBinary
+func (s *Sketch) UnmarshalBinary(data []byte) error {
+	if len(data) < 9 || data[0] != sketchVersion {
+		return errors.New("set: invalid sketch encoding")
+	}
+	maxSize := int(binary.BigEndian.Uint32(data[1:]))
+	cells := int(binary.BigEndian.Uint32(data[5:]))
+	invalid := fmt.Errorf("set: invalid sketch encoding of %d bytes for %d cells", len(data), cells)
+	// the sizes come from another host, and on 32-bit platforms they can be negative
+	if cells < sketchHashes || cells%sketchHashes != 0 || maxSize < 0 || maxSize > len(data) {
+		return invalid
+	}
+	cellSize := 16 + 4 + maxSize
+	// dividing rather than multiplying can't overflow
+	if n := len(data) - 9; n%cellSize != 0 || n/cellSize != cells {
+		return invalid
+	}
+	*s = *NewSketch(cells, maxSize)
+	data = data[9:]
+	for i := range s.cells {
+		c := &s.cells[i]
+		c.count = int64(binary.BigEndian.Uint64(data))
+		c.hashSum = binary.BigEndian.Uint64(data[8:])
+		copy(c.keySum, data[16:cellSize])
+		data = data[cellSize:]
+	}
+	return nil
+}
+
+func (s *Sketch) update(e []byte, count int64) error {
+	if len(e) > s.maxSize {
+		return fmt.Errorf("%w: %d bytes, the maximum is %d", ErrElementTooLarge, len(e), s.maxSize)
+	}
+	for _, i := range s.indices(e) {
+		s.apply(i, e, count)
+	}
+	return nil
+}
+
+// apply adds count copies of e to the cell, or removes them if count is negative
+func (s *Sketch) apply(i int, e []byte, count int64) {
+	c := &s.cells[i]
+	c.count += count
+	// every copy toggles the sums, so only an odd number of them changes them
+	if count%2 != 0 {
+		c.hashSum ^= sketchChecksum(e)
+		binary.BigEndian.PutUint32(c.keySum, binary.BigEndian.Uint32(c.keySum)^uint32(len(e)))
+		xorBytes(c.keySum[4:], e)
+	}
+}
+
+// pure returns the element of a cell holding exactly one element, inserted or deleted
+func (s *Sketch) pure(i int) ([]byte, bool) {
+	c := &s.cells[i]
+	if c.count != 1 && c.count != -1 {
+		return nil, false
+	}
+	n := int(binary.BigEndian.Uint32(c.keySum))
+	if n > s.maxSize {
+		return nil, false
+	}
+	e := append([]byte(nil), c.keySum[4:4+n]...)
+	// the padding must be zero and the checksum must match, or several elements are mixed in the cell
+	for _, b := range c.keySum[4+n:] {
+		if b != 0 {
+			return nil, false
+		}
+	}
+	if sketchChecksum(e) != c.hashSum {
+		return nil, false
+	}
+	// a real element hashes to every cell it is in, a crafted cell may hold one that doesn't
+	for _, j := range s.indices(e) {
+		if j == i {
+			return e, true
+		}
+	}
+	return nil, false
+}
+
+// indices returns the cell of e in every subtable
+func (s *Sketch) indices(e []byte) [sketchHashes]int {
+	var indices [sketchHashes]int
+	part := len(s.cells) / sketchHashes
+	for k := range indices {
+		indices[k] = k*part + int(sketchHash(byte(k), e)%uint64(part))
+	}
+	return indices
+}
+
+func (s *Sketch) clone() *Sketch {
+	c := NewSketch(len(s.cells), s.maxSize)
+	for i := range s.cells {
+		c.cells[i].count = s.cells[i].count
+		c.cells[i].hashSum = s.cells[i].hashSum
+		copy(c.cells[i].keySum, s.cells[i].keySum)
+	}
+	return c
+}
+
+func (c *sketchCell) empty() bool {
+	if c.count != 0 || c.hashSum != 0 {
+		return false
+	}
+	for _, b := range c.keySum {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// sketchHash returns the FNV-1a hash of e seeded with the subtable
+func sketchHash(seed byte, e []byte) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte{seed})
+	h.Write(e)
+	return h.Sum64()
+}
+
+// sketchChecksum tells a cell holding a single element from one holding several
+func sketchChecksum(e []byte) uint64 {
+	return sketchHash(sketchHashes, e)
+}
+
+func xorBytes(dst, src []byte) {
+	for i := range src {
+		dst[i] ^= src[i]
+	}
+}
+
+// Reconciler finds the differences between two sets on different hosts by exchanging sketches of them.
+// Both sides must use the same encoding and maximum element size.
+type Reconciler[T comparable] struct {
+	encode  func(T) []byte
+	decode  func([]byte) (T, error)
+	maxSize int
+	// InitialCells is the size of the first sketch Reconcile tries, 64 by default
+	InitialCells int
+	// MaxCells is the size at which Reconcile gives up, 1<<20 by default
+	MaxCells int
+}
+
+// NewReconciler returns a new reconciler for elements that encode to at most maxElementSize bytes
+func NewReconciler[T comparable](encode func(T) []byte, decode func([]byte) (T, error), maxElementSize int) *Reconciler[T] {
+	return &Reconciler[T]{
+		encode:       encode,
+		decode:       decode,
+		maxSize:      maxElementSize,
+		InitialCells: 64,
+		MaxCells:     1 << 20,
+	}
+}
+
+// Sketch returns a sketch of the set with the given number of cells
+func (r *Reconciler[T]) Sketch(s *Set[T], cells int) (*Sketch, error) {
+	sketch := NewSketch(cells, r.maxSize)
+	for k := range s.m {
+		if err := sketch.Insert(r.encode(k)); err != nil {
+			return nil, err
+		}
+	}
+	return sketch, nil
+}
+
+// Reconcile returns the elements only in mine and the ones only in the remote set
+// remote returns a sketch of the remote set with the given number of cells, usually by asking the other host for it.
+// Reconcile starts with InitialCells and doubles the size until the difference decodes,
+// and returns ErrSketchDecode if it still doesn't at MaxCells.
+func (r *Reconciler[T]) Reconcile(mine *Set[T], remote func(cells int) (*Sketch, error)) (onlyMine, onlyYours *Set[T], err error) {
+	cells := r.InitialCells
+	if cells < 1 {
+		cells = 1
+	}
+	for ; cells <= r.MaxCells; cells *= 2 {
+		local, err := r.Sketch(mine, cells)
+		if err != nil {
+			return nil, nil, err
+		}
+		theirs, err := remote(cells)
+		if err != nil {
+			return nil, nil, err
+		}
+		diff, err := local.Subtract(theirs)
+		if err != nil {
+			return nil, nil, err
+		}
+		inserted, deleted, err := diff.Decode()
+		if errors.Is(err, ErrSketchDecode) {
+			continue
+		}
+		if onlyMine, err = r.decodeAll(inserted); err != nil {
+			return nil, nil, err
+		}
+		if onlyYours, err = r.decodeAll(deleted); err != nil {
+			return nil, nil, err
+		}
+		return onlyMine, onlyYours, nil
+	}
+	return nil, nil, ErrSketchDecode
+}
+
+func (r *Reconciler[T]) decodeAll(elems [][]byte) (*Set[T], error) {
+	s := NewSet[T]()
+	for _, b := range elems {
+		e, err := r.decode(b)
+		if err != nil {
+			return nil, err
+		}
+		s.Add(e)
+	}
+	return s, nil
+}
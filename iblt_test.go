@@ -0,0 +1,204 @@
+package set
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/drkennetz/set/settest"
+)
+
+func encodeInt(i int) []byte {
+	return binary.BigEndian.AppendUint64(nil, uint64(i))
+}
+
+func decodeInt(b []byte) (int, error) {
+	if len(b) != 8 {
+		return 0, errors.New("expected 8 bytes")
+	}
+	return int(binary.BigEndian.Uint64(b)), nil
+}
+
+func TestSketch(t *testing.T) {
+	mine, yours := NewSketch(30, 8), NewSketch(30, 8)
+	for i := 0; i < 1000; i++ {
+		mine.Insert(encodeInt(i))
+		yours.Insert(encodeInt(i))
+	}
+	mine.Insert([]byte("mine"))
+	mine.Insert([]byte{})
+	yours.Insert([]byte("yours"))
+	mine.Delete(encodeInt(5))
+	diff, err := mine.Subtract(yours)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inserted, deleted, err := diff.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	settest.AssertEqual[string](t, bytesSet(inserted), NewSetFromSlice([]string{"mine", ""}))
+	settest.AssertEqual[string](t, bytesSet(deleted), NewSetFromSlice([]string{"yours", string(encodeInt(5))}))
+	if diff.Cells() != 30 {
+		t.Errorf("Expected 30 cells, got %d", diff.Cells())
+	}
+}
+
+func bytesSet(elems [][]byte) *Set[string] {
+	s := NewSet[string]()
+	for _, e := range elems {
+		s.Add(string(e))
+	}
+	return s
+}
+
+func TestSketch_Errors(t *testing.T) {
+	s := NewSketch(1, 4)
+	if s.Cells() != 3 {
+		t.Errorf("Expected the minimum of 3 cells, got %d", s.Cells())
+	}
+	if err := s.Insert([]byte("too long")); !errors.Is(err, ErrElementTooLarge) {
+		t.Errorf("Expected ErrElementTooLarge, got %v", err)
+	}
+	if _, err := s.Subtract(NewSketch(6, 4)); !errors.Is(err, ErrSketchMismatch) {
+		t.Errorf("Expected ErrSketchMismatch, got %v", err)
+	}
+	for i := 0; i < 100; i++ {
+		s.Insert(encodeInt(i)[4:])
+	}
+	if _, _, err := s.Decode(); !errors.Is(err, ErrSketchDecode) {
+		t.Errorf("Expected ErrSketchDecode, got %v", err)
+	}
+}
+
+func TestSketch_Binary(t *testing.T) {
+	s := NewSketch(30, 8)
+	for i := 0; i < 5; i++ {
+		s.Insert(encodeInt(i))
+	}
+	data, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Sketch
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	inserted, _, err := decoded.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inserted) != 5 {
+		t.Errorf("Expected 5 elements, got %d", len(inserted))
+	}
+	for _, bad := range [][]byte{
+		nil,
+		{2, 0, 0, 0, 0, 0, 0, 0, 0},
+		{1, 0, 0, 0, 0, 0, 0, 0, 0}, // no cells
+		{1, 255, 255, 255, 255, 255, 255, 255, 255}, // sizes that overflow
+		data[:len(data)-1],
+	} {
+		if err := decoded.UnmarshalBinary(bad); err == nil {
+			t.Errorf("Expected an error for %v", bad)
+		}
+	}
+	// a crafted sketch whose pure looking cells hold elements that don't hash to them
+	crafted := []byte{1, 0, 0, 0, 1, 0, 0, 0, 6}
+	for i := 0; i < 6; i++ {
+		var cell [21]byte
+		if e := map[int]byte{2: 6, 4: 1}[i]; e != 0 {
+			binary.BigEndian.PutUint64(cell[:], 1)
+			binary.BigEndian.PutUint64(cell[8:], sketchChecksum([]byte{e}))
+			cell[19], cell[20] = 1, e
+		}
+		crafted = append(crafted, cell[:]...)
+	}
+	if err := decoded.UnmarshalBinary(crafted); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := decoded.Decode(); !errors.Is(err, ErrSketchDecode) {
+		t.Errorf("Expected ErrSketchDecode for a crafted sketch, got %v", err)
+	}
+}
+
+func TestReconciler(t *testing.T) {
+	mine, yours := NewSet[int](), NewSet[int]()
+	for i := 0; i < 20000; i++ {
+		mine.Add(i)
+		yours.Add(i)
+	}
+	wantMine, wantYours := NewSet[int](), NewSet[int]()
+	for i := 0; i < 20; i++ {
+		mine.Remove(i * 1000)
+		wantYours.Add(i * 1000)
+		yours.Add(-i - 1)
+		wantYours.Add(-i - 1)
+	}
+	for i := 0; i < 10; i++ {
+		mine.Add(200000 + i)
+		wantMine.Add(200000 + i)
+	}
+	r := NewReconciler(encodeInt, decodeInt, 8)
+	r.InitialCells = 4
+	var sizes []int
+	onlyMine, onlyYours, err := r.Reconcile(mine, func(cells int) (*Sketch, error) {
+		sizes = append(sizes, cells)
+		// send the sketch over the wire
+		sketch, err := r.Sketch(yours, cells)
+		if err != nil {
+			return nil, err
+		}
+		data, _ := sketch.MarshalBinary()
+		var received Sketch
+		return &received, received.UnmarshalBinary(data)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	settest.AssertEqual[int](t, onlyMine, wantMine)
+	settest.AssertEqual[int](t, onlyYours, wantYours)
+	if len(sizes) < 2 {
+		t.Errorf("Expected the first sketch of %d cells to be too small, tried %v", r.InitialCells, sizes)
+	}
+}
+
+func TestReconciler_Errors(t *testing.T) {
+	mine := NewSetFromSlice([]int{1, 2, 3})
+	r := NewReconciler(encodeInt, decodeInt, 8)
+	r.MaxCells = 8
+	tooMany := NewSetFromSlice(rangeSet(100, 200).ToSlice())
+	if _, _, err := r.Reconcile(mine, func(cells int) (*Sketch, error) {
+		return r.Sketch(tooMany, cells)
+	}); !errors.Is(err, ErrSketchDecode) {
+		t.Errorf("Expected ErrSketchDecode, got %v", err)
+	}
+	r.MaxCells = 1 << 10
+	failed := errors.New("unreachable")
+	if _, _, err := r.Reconcile(mine, func(int) (*Sketch, error) {
+		return nil, failed
+	}); !errors.Is(err, failed) {
+		t.Errorf("Expected the error of remote, got %v", err)
+	}
+	if _, _, err := r.Reconcile(mine, func(cells int) (*Sketch, error) {
+		return NewSketch(cells+3, 8), nil
+	}); !errors.Is(err, ErrSketchMismatch) {
+		t.Errorf("Expected ErrSketchMismatch, got %v", err)
+	}
+	short := NewReconciler(func(i int) []byte { return encodeInt(i)[4:] }, decodeInt, 8)
+	if _, _, err := short.Reconcile(mine, func(cells int) (*Sketch, error) {
+		return NewSketch(cells, 8), nil
+	}); err == nil {
+		t.Error("Expected the decode error for mine")
+	}
+	if _, _, err := short.Reconcile(NewSet[int](), func(cells int) (*Sketch, error) {
+		return short.Sketch(mine, cells)
+	}); err == nil {
+		t.Error("Expected the decode error for yours")
+	}
+	if _, _, err := NewReconciler(encodeInt, decodeInt, 4).Reconcile(mine, nil); !errors.Is(err, ErrElementTooLarge) {
+		t.Errorf("Expected ErrElementTooLarge, got %v", err)
+	}
+	if _, err := r.Sketch(NewSetFromSlice([]int{1}), 3); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
@@ -0,0 +1,221 @@
+package set
+
+import "fmt"
+
+// DuplicatePolicy decides what KeyedSet.Add does with a value whose key is already in the set
+type DuplicatePolicy int
+
+const (
+	// KeepExisting keeps the value that was added first
+	KeepExisting DuplicatePolicy = iota
+	// ReplaceExisting replaces the stored value with the new one
+	ReplaceExisting
+)
+
+// Preference picks which operand of a KeyedSet operation the values of shared keys come from
+type Preference int
+
+const (
+	// PreferLeft takes the values of shared keys from the receiver
+	PreferLeft Preference = iota
+	// PreferRight takes the values of shared keys from the argument
+	PreferRight
+)
+
+// KeyedSet is a set of values that are unique by a key derived from them.
+// Two values with the same key are duplicates even if they differ otherwise,
+// and the set algebra compares keys only.
+type KeyedSet[K comparable, V any] struct {
+	key    func(V) K
+	policy DuplicatePolicy
+	m      map[K]V
+}
+
+// NewKeyedSet returns a new keyed set deriving keys with the function
+func NewKeyedSet[K comparable, V any](key func(V) K, policy DuplicatePolicy) *KeyedSet[K, V] {
+	return &KeyedSet[K, V]{
+		key:    key,
+		policy: policy,
+		m:      make(map[K]V),
+	}
+}
+
+// NewKeyedSetFromSlice returns a new keyed set from a slice, the policy decides between duplicates in the slice
+func NewKeyedSetFromSlice[K comparable, V any](key func(V) K, policy DuplicatePolicy, s []V) *KeyedSet[K, V] {
+	set := NewKeyedSet(key, policy)
+	for _, v := range s {
+		set.Add(v)
+	}
+	return set
+}
+
+// Add adds a value to the set, or keeps or replaces the stored value with the same key depending on the policy
+// It returns true if the key was not in the set
+func (s *KeyedSet[K, V]) Add(v V) bool {
+	k := s.key(v)
+	_, ok := s.m[k]
+	if !ok || s.policy == ReplaceExisting {
+		s.m[k] = v
+	}
+	return !ok
+}
+
+// Get returns the value stored for the key and true, or the zero value of V and false if the key is not in the set
+func (s *KeyedSet[K, V]) Get(k K) (V, bool) {
+	v, ok := s.m[k]
+	return v, ok
+}
+
+// ContainsKey returns true if the set contains a value with the key
+func (s *KeyedSet[K, V]) ContainsKey(k K) bool {
+	_, ok := s.m[k]
+	return ok
+}
+
+// Contains returns true if the set contains a value with the same key as v
+func (s *KeyedSet[K, V]) Contains(v V) bool {
+	return s.ContainsKey(s.key(v))
+}
+
+// RemoveKey removes the value with the key from the set
+func (s *KeyedSet[K, V]) RemoveKey(k K) {
+	delete(s.m, k)
+}
+
+// Remove removes the value with the same key as v from the set
+func (s *KeyedSet[K, V]) Remove(v V) {
+	s.RemoveKey(s.key(v))
+}
+
+// Keys returns the keys of the set as a new set
+func (s *KeyedSet[K, V]) Keys() *Set[K] {
+	keys := make(map[K]struct{}, len(s.m))
+	for k := range s.m {
+		keys[k] = struct{}{}
+	}
+	return &Set[K]{m: keys}
+}
+
+// Values returns a slice of the values in the set
+func (s *KeyedSet[K, V]) Values() []V {
+	values := make([]V, 0, len(s.m))
+	for _, v := range s.m {
+		values = append(values, v)
+	}
+	return values
+}
+
+// Union returns the values whose keys are in either set as a new set
+// The values of keys in both sets come from the preferred operand
+func (s *KeyedSet[K, V]) Union(s2 *KeyedSet[K, V], prefer Preference) *KeyedSet[K, V] {
+	s3 := s.Copy()
+	for k, v := range s2.m {
+		if _, ok := s3.m[k]; !ok || prefer == PreferRight {
+			s3.m[k] = v
+		}
+	}
+	return s3
+}
+
+// Intersection returns the values whose keys are in both sets as a new set
+// The values come from the preferred operand
+func (s *KeyedSet[K, V]) Intersection(s2 *KeyedSet[K, V], prefer Preference) *KeyedSet[K, V] {
+	s3 := s.empty()
+	from, other := s.m, s2.m
+	if prefer == PreferRight {
+		from, other = other, from
+	}
+	for k, v := range from {
+		if _, ok := other[k]; ok {
+			s3.m[k] = v
+		}
+	}
+	return s3
+}
+
+// Difference returns the values in s whose keys are not in s2 as a new set
+func (s *KeyedSet[K, V]) Difference(s2 *KeyedSet[K, V]) *KeyedSet[K, V] {
+	s3 := s.empty()
+	for k, v := range s.m {
+		if _, ok := s2.m[k]; !ok {
+			s3.m[k] = v
+		}
+	}
+	return s3
+}
+
+// SymmetricDifference returns the values whose keys are in one of the sets, but not both
+func (s *KeyedSet[K, V]) SymmetricDifference(s2 *KeyedSet[K, V]) *KeyedSet[K, V] {
+	s3 := s.Difference(s2)
+	for k, v := range s2.m {
+		if _, ok := s.m[k]; !ok {
+			s3.m[k] = v
+		}
+	}
+	return s3
+}
+
+// IsSubset returns true if every key in s is in s2
+func (s *KeyedSet[K, V]) IsSubset(s2 *KeyedSet[K, V]) bool {
+	for k := range s.m {
+		if _, ok := s2.m[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuperset returns true if every key in s2 is in s
+func (s *KeyedSet[K, V]) IsSuperset(s2 *KeyedSet[K, V]) bool {
+	return s2.IsSubset(s)
+}
+
+// IsEqual returns true if s and s2 contain the same keys, their values are not compared
+func (s *KeyedSet[K, V]) IsEqual(s2 *KeyedSet[K, V]) bool {
+	return len(s.m) == len(s2.m) && s.IsSubset(s2)
+}
+
+// Filter returns a new set containing only the values that satisfy the predicate
+func (s *KeyedSet[K, V]) Filter(predicate func(V) bool) *KeyedSet[K, V] {
+	s2 := s.empty()
+	for k, v := range s.m {
+		if predicate(v) {
+			s2.m[k] = v
+		}
+	}
+	return s2
+}
+
+// Copy returns a copy of the set, the values themselves are not copied
+func (s *KeyedSet[K, V]) Copy() *KeyedSet[K, V] {
+	s2 := s.empty()
+	for k, v := range s.m {
+		s2.m[k] = v
+	}
+	return s2
+}
+
+// Len returns the number of values in the set
+func (s *KeyedSet[K, V]) Len() int {
+	return len(s.m)
+}
+
+// Clear removes all values from the set
+func (s *KeyedSet[K, V]) Clear() {
+	s.m = make(map[K]V)
+}
+
+// IsEmpty returns true if the set is empty
+func (s *KeyedSet[K, V]) IsEmpty() bool {
+	return s.Len() == 0
+}
+
+// String returns a string representation of the set
+func (s *KeyedSet[K, V]) String() string {
+	return fmt.Sprintf("%v", s.Values())
+}
+
+// empty returns a new empty set with the same key function and policy as s
+func (s *KeyedSet[K, V]) empty() *KeyedSet[K, V] {
+	return NewKeyedSet(s.key, s.policy)
+}
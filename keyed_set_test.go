@@ -0,0 +1,89 @@
+package set
+
+import (
+	"testing"
+
+	"github.com/drkennetz/set/settest"
+)
+
+type release struct {
+	Name    string
+	Version string
+}
+
+func releaseName(r release) string {
+	return r.Name
+}
+
+func versions(s *KeyedSet[string, release]) map[string]string {
+	m := make(map[string]string)
+	for _, r := range s.Values() {
+		m[r.Name] = r.Version
+	}
+	return m
+}
+
+func TestKeyedSet_Add(t *testing.T) {
+	keep := NewKeyedSet(releaseName, KeepExisting)
+	replace := NewKeyedSet(releaseName, ReplaceExisting)
+	for _, s := range []*KeyedSet[string, release]{keep, replace} {
+		if !s.Add(release{"set", "v1"}) {
+			t.Error("KeyedSet.Add() returned false for a new key")
+		}
+		if s.Add(release{"set", "v2"}) {
+			t.Error("KeyedSet.Add() returned true for an existing key")
+		}
+		if s.Len() != 1 || !s.ContainsKey("set") || !s.Contains(release{Name: "set"}) {
+			t.Errorf("Expected a single value for set, got %v", s)
+		}
+	}
+	if r, _ := keep.Get("set"); r.Version != "v1" {
+		t.Errorf("Expected KeepExisting to keep v1, got %s", r.Version)
+	}
+	if r, _ := replace.Get("set"); r.Version != "v2" {
+		t.Errorf("Expected ReplaceExisting to store v2, got %s", r.Version)
+	}
+	if _, ok := keep.Get("missing"); ok {
+		t.Error("KeyedSet.Get() returned true for a missing key")
+	}
+	keep.Remove(release{Name: "set"})
+	replace.RemoveKey("set")
+	if !keep.IsEmpty() || !replace.IsEmpty() {
+		t.Errorf("Expected empty sets, got %v and %v", keep, replace)
+	}
+}
+
+func TestKeyedSet_Algebra(t *testing.T) {
+	a := NewKeyedSetFromSlice(releaseName, KeepExisting, []release{{"x", "a1"}, {"y", "a1"}})
+	b := NewKeyedSetFromSlice(releaseName, KeepExisting, []release{{"y", "b1"}, {"z", "b1"}})
+
+	for prefer, want := range map[Preference]map[string]string{
+		PreferLeft:  {"x": "a1", "y": "a1", "z": "b1"},
+		PreferRight: {"x": "a1", "y": "b1", "z": "b1"},
+	} {
+		if got := versions(a.Union(b, prefer)); len(got) != 3 || got["x"] != want["x"] || got["y"] != want["y"] || got["z"] != want["z"] {
+			t.Errorf("Union(%d): expected %v, got %v", prefer, want, got)
+		}
+	}
+	if got := versions(a.Intersection(b, PreferLeft)); len(got) != 1 || got["y"] != "a1" {
+		t.Errorf("Expected {y: a1}, got %v", got)
+	}
+	if got := versions(a.Intersection(b, PreferRight)); len(got) != 1 || got["y"] != "b1" {
+		t.Errorf("Expected {y: b1}, got %v", got)
+	}
+	settest.AssertEqual[string](t, a.Difference(b).Keys(), NewSetFromSlice([]string{"x"}))
+	settest.AssertEqual[string](t, a.SymmetricDifference(b).Keys(), NewSetFromSlice([]string{"x", "z"}))
+	settest.AssertEqual[string](t, a.Filter(func(r release) bool { return r.Name == "y" }).Keys(), NewSetFromSlice([]string{"y"}))
+
+	if a.IsSubset(b) || a.IsSuperset(b) || a.IsEqual(b) {
+		t.Error("Expected a and b to be unrelated")
+	}
+	c := NewKeyedSetFromSlice(releaseName, KeepExisting, []release{{"x", "c1"}, {"y", "c1"}})
+	if !a.IsEqual(c) || !a.IsSubset(c) || !c.IsSuperset(a) {
+		t.Error("Expected sets with the same keys to be equal")
+	}
+	a.Clear()
+	if !a.IsEmpty() || a.String() != "[]" {
+		t.Errorf("Expected empty set, got %v", a)
+	}
+}
@@ -0,0 +1,96 @@
+package set
+
+import "sort"
+
+// MergeSide names one of the two changed versions in a three-way merge
+type MergeSide int
+
+const (
+	// Ours is the first changed version passed to Merge3
+	Ours MergeSide = iota
+	// Theirs is the second changed version passed to Merge3
+	Theirs
+)
+
+// String returns the name of the side
+func (s MergeSide) String() string {
+	if s == Ours {
+		return "ours"
+	}
+	return "theirs"
+}
+
+// MergeStrategy decides the contested elements of Merge3
+type MergeStrategy int
+
+const (
+	// PreferRemove takes the removal of a contested element, as git takes a change made on one side only
+	PreferRemove MergeStrategy = iota
+	// PreferAdd keeps a contested element, undoing a removal the other side didn't make
+	PreferAdd
+)
+
+// Contested is an element of base that one side removed and the other kept unchanged
+// In a git-style merge this is not a conflict, the removal is taken, but Merge3 reports these
+// elements so a caller can review them or, with PreferAdd or Merge3Func, keep them.
+type Contested[T comparable] struct {
+	Elem T
+	// RemovedBy is the side that removed the element
+	RemovedBy MergeSide
+	// Kept is true if the resolution kept the element in the merged set
+	Kept bool
+}
+
+// Merge3 merges the changes ours and theirs each made to base and returns the contested elements with their resolution
+// Elements added by either side are added, and elements removed by either side are removed unless the strategy keeps them.
+// Sets can't conflict the way lines of text do: an element added by one side wasn't in base, so the other can't have removed it.
+// With PreferRemove the merge is the git-style one. The contested elements are returned in a fixed order, the same in every process.
+func Merge3[T comparable](base, ours, theirs *Set[T], strategy MergeStrategy) (*Set[T], []Contested[T]) {
+	return Merge3Func(base, ours, theirs, func(Contested[T]) bool {
+		return strategy == PreferAdd
+	})
+}
+
+// Merge3Func merges the changes ours and theirs each made to base like Merge3
+// resolve is called with every contested element, in the order they are returned, and returns true to keep it
+func Merge3Func[T comparable](base, ours, theirs *Set[T], resolve func(Contested[T]) bool) (*Set[T], []Contested[T]) {
+	merged := NewSet[T]()
+	var contested []Contested[T]
+	for k := range ours.m {
+		_, inBase := base.m[k]
+		_, inTheirs := theirs.m[k]
+		if inTheirs || !inBase {
+			// kept by both, or added by ours
+			merged.m[k] = struct{}{}
+		} else {
+			contested = append(contested, Contested[T]{Elem: k, RemovedBy: Theirs})
+		}
+	}
+	for k := range theirs.m {
+		_, inBase := base.m[k]
+		_, inOurs := ours.m[k]
+		if inOurs {
+			// handled with ours
+			continue
+		}
+		if !inBase {
+			merged.m[k] = struct{}{}
+		} else {
+			contested = append(contested, Contested[T]{Elem: k, RemovedBy: Ours})
+		}
+	}
+	// order by the canonical encoding of the fingerprints, which doesn't depend on the map order
+	keys := make(map[T]string, len(contested))
+	for _, c := range contested {
+		keys[c.Elem] = string(appendCanonical(nil, c.Elem))
+	}
+	sort.Slice(contested, func(i, j int) bool {
+		return keys[contested[i].Elem] < keys[contested[j].Elem]
+	})
+	for i := range contested {
+		if contested[i].Kept = resolve(contested[i]); contested[i].Kept {
+			merged.m[contested[i].Elem] = struct{}{}
+		}
+	}
+	return merged, contested
+}
@@ -0,0 +1,78 @@
+package set
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/drkennetz/set/settest"
+)
+
+func TestMerge3(t *testing.T) {
+	base := NewSetFromSlice([]string{"a", "b", "c", "d"})
+	ours := NewSetFromSlice([]string{"a", "b", "x"})   // removed c, d, added x
+	theirs := NewSetFromSlice([]string{"a", "c", "y"}) // removed b, d, added y
+
+	// the removals made by one side are taken, as git takes a change made on one side only
+	merged, contested := Merge3(base, ours, theirs, PreferRemove)
+	settest.AssertEqual[string](t, merged, NewSetFromSlice([]string{"a", "x", "y"}))
+	want := []Contested[string]{{Elem: "b", RemovedBy: Theirs}, {Elem: "c", RemovedBy: Ours}}
+	if !reflect.DeepEqual(contested, want) {
+		t.Errorf("Expected %+v, got %+v", want, contested)
+	}
+
+	merged, contested = Merge3(base, ours, theirs, PreferAdd)
+	settest.AssertEqual[string](t, merged, NewSetFromSlice([]string{"a", "b", "c", "x", "y"}))
+	for _, c := range contested {
+		if !c.Kept {
+			t.Errorf("Expected PreferAdd to keep %v", c.Elem)
+		}
+	}
+}
+
+func TestMerge3_Order(t *testing.T) {
+	base := rangeSet(0, 100)
+	ours := rangeSet(0, 50)
+	theirs := rangeSet(25, 100)
+	_, first := Merge3(base, ours, theirs, PreferRemove)
+	if len(first) != 75 {
+		t.Fatalf("Expected 75 contested elements, got %d", len(first))
+	}
+	for i := 0; i < 10; i++ {
+		if _, contested := Merge3(base, ours, theirs, PreferRemove); !reflect.DeepEqual(contested, first) {
+			t.Fatalf("Expected the contested elements in the same order, got %v and %v", first, contested)
+		}
+	}
+}
+
+func TestMerge3Func(t *testing.T) {
+	base := NewSetFromSlice([]int{1, 2, 3})
+	ours := NewSetFromSlice([]int{1})
+	theirs := NewSetFromSlice([]int{2, 3})
+	// keep what theirs kept, drop what ours kept
+	merged, contested := Merge3Func(base, ours, theirs, func(c Contested[int]) bool {
+		return c.RemovedBy == Ours
+	})
+	settest.AssertEqual[int](t, merged, NewSetFromSlice([]int{2, 3}))
+	if len(contested) != 3 {
+		t.Errorf("Expected 3 contested elements, got %v", contested)
+	}
+}
+
+func TestMerge3_NoContested(t *testing.T) {
+	base := NewSetFromSlice([]int{1, 2})
+	merged, contested := Merge3(base, base.Copy(), NewSetFromSlice([]int{1, 2, 3}), PreferRemove)
+	settest.AssertEqual[int](t, merged, NewSetFromSlice([]int{1, 2, 3}))
+	if len(contested) != 0 {
+		t.Errorf("Expected no contested elements, got %v", contested)
+	}
+	merged, contested = Merge3(base, NewSet[int](), NewSet[int](), PreferAdd)
+	if !merged.IsEmpty() || len(contested) != 0 {
+		t.Errorf("Expected elements removed by both sides to be removed, got %v %v", merged, contested)
+	}
+}
+
+func TestMergeSide_String(t *testing.T) {
+	if Ours.String() != "ours" || Theirs.String() != "theirs" {
+		t.Errorf("Expected ours and theirs, got %s and %s", Ours, Theirs)
+	}
+}
@@ -0,0 +1,213 @@
+package set
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"sort"
+)
+
+// MerkleSet is a Merkle tree over the encoded elements of a set, in sorted order.
+// The tree is built as in RFC 6962: leaves are hashed as SHA-256(0x00 || leaf) and nodes as
+// SHA-256(0x01 || left || right). Publishing the root commits to the set: an inclusion proof shows
+// an element is in it, and an exclusion proof shows the two sorted neighbours of a missing element
+// are adjacent leaves. Like the signed tree heads of certificate transparency, the commitment is the
+// root together with the number of leaves: the proofs only hold for a tree size the verifier trusts.
+// A MerkleSet is a snapshot, changes to the set after it was built don't show.
+type MerkleSet[T comparable] struct {
+	encode func(T) []byte
+	leaves [][]byte
+	// levels[0] holds the leaf hashes and the last level holds the root
+	levels [][][sha256.Size]byte
+}
+
+// InclusionProof is the audit path from the leaf at Index to the root
+type InclusionProof struct {
+	Index int
+	Path  [][sha256.Size]byte
+}
+
+// ExclusionProof shows that an element is not in a tree
+// Left and Right are the encoded neighbours of the element in sorted order, with their inclusion proofs.
+// LeftProof is nil if the element sorts before every leaf, and RightProof is nil if it sorts after every leaf.
+type ExclusionProof struct {
+	Left       []byte
+	LeftProof  *InclusionProof
+	Right      []byte
+	RightProof *InclusionProof
+}
+
+// NewMerkleSet returns a Merkle tree of the elements of the set
+// encode must give every element a different encoding, elements with the same encoding become one leaf
+func NewMerkleSet[T comparable](s *Set[T], encode func(T) []byte) *MerkleSet[T] {
+	leaves := make([][]byte, 0, s.Len())
+	for k := range s.m {
+		leaves = append(leaves, encode(k))
+	}
+	sort.Slice(leaves, func(i, j int) bool {
+		return bytes.Compare(leaves[i], leaves[j]) < 0
+	})
+	// drop duplicate encodings
+	unique := leaves[:0]
+	for i, leaf := range leaves {
+		if i == 0 || !bytes.Equal(leaf, leaves[i-1]) {
+			unique = append(unique, leaf)
+		}
+	}
+	m := &MerkleSet[T]{encode: encode, leaves: unique}
+	level := make([][sha256.Size]byte, len(unique))
+	for i, leaf := range unique {
+		level[i] = leafHash(leaf)
+	}
+	m.levels = append(m.levels, level)
+	// pairing the nodes of each level and promoting an odd last node gives the tree of RFC 6962
+	for len(level) > 1 {
+		next := make([][sha256.Size]byte, (len(level)+1)/2)
+		for i := range next {
+			if 2*i+1 < len(level) {
+				next[i] = nodeHash(level[2*i], level[2*i+1])
+			} else {
+				next[i] = level[2*i]
+			}
+		}
+		m.levels = append(m.levels, next)
+		level = next
+	}
+	return m
+}
+
+// Root returns the root hash of the tree, the SHA-256 of nothing for an empty set
+func (m *MerkleSet[T]) Root() [sha256.Size]byte {
+	if len(m.leaves) == 0 {
+		return sha256.Sum256(nil)
+	}
+	return m.levels[len(m.levels)-1][0]
+}
+
+// Len returns the number of leaves in the tree, which verifiers need along with the root
+func (m *MerkleSet[T]) Len() int {
+	return len(m.leaves)
+}
+
+// Contains returns true if the tree has a leaf for the element
+func (m *MerkleSet[T]) Contains(e T) bool {
+	_, ok := m.search(m.encode(e))
+	return ok
+}
+
+// ProveInclusion returns a proof that the element is in the set, or false if it isn't
+func (m *MerkleSet[T]) ProveInclusion(e T) (*InclusionProof, bool) {
+	i, ok := m.search(m.encode(e))
+	if !ok {
+		return nil, false
+	}
+	return m.proof(i), true
+}
+
+// ProveExclusion returns a proof that the element is not in the set, or false if it is
+func (m *MerkleSet[T]) ProveExclusion(e T) (*ExclusionProof, bool) {
+	i, ok := m.search(m.encode(e))
+	if ok {
+		return nil, false
+	}
+	proof := &ExclusionProof{}
+	if i > 0 {
+		proof.Left, proof.LeftProof = m.leaves[i-1], m.proof(i-1)
+	}
+	if i < len(m.leaves) {
+		proof.Right, proof.RightProof = m.leaves[i], m.proof(i)
+	}
+	return proof, true
+}
+
+// VerifyInclusion returns true if the proof shows the encoded element is in the tree with the root and size
+func VerifyInclusion(root [sha256.Size]byte, treeSize int, leaf []byte, proof *InclusionProof) bool {
+	if proof == nil || proof.Index < 0 || proof.Index >= treeSize {
+		return false
+	}
+	// the verification algorithm of RFC 9162, section 2.1.3.2
+	fn, sn := proof.Index, treeSize-1
+	r := leafHash(leaf)
+	for _, p := range proof.Path {
+		if sn == 0 {
+			return false
+		}
+		if fn&1 == 1 || fn == sn {
+			r = nodeHash(p, r)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			r = nodeHash(r, p)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	return sn == 0 && r == root
+}
+
+// VerifyExclusion returns true if the proof shows the encoded element is not in the tree with the root and size
+func VerifyExclusion(root [sha256.Size]byte, treeSize int, leaf []byte, proof *ExclusionProof) bool {
+	if proof == nil {
+		return false
+	}
+	if treeSize == 0 {
+		return proof.LeftProof == nil && proof.RightProof == nil && root == sha256.Sum256(nil)
+	}
+	if proof.LeftProof == nil && proof.RightProof == nil {
+		return false
+	}
+	if proof.LeftProof != nil {
+		if bytes.Compare(proof.Left, leaf) >= 0 || !VerifyInclusion(root, treeSize, proof.Left, proof.LeftProof) {
+			return false
+		}
+		// without a right neighbour, the left one must be the last leaf
+		if proof.RightProof == nil && proof.LeftProof.Index != treeSize-1 {
+			return false
+		}
+	}
+	if proof.RightProof != nil {
+		if bytes.Compare(leaf, proof.Right) >= 0 || !VerifyInclusion(root, treeSize, proof.Right, proof.RightProof) {
+			return false
+		}
+		// without a left neighbour, the right one must be the first leaf
+		if proof.LeftProof == nil && proof.RightProof.Index != 0 {
+			return false
+		}
+	}
+	// with both neighbours, they must be adjacent leaves
+	return proof.LeftProof == nil || proof.RightProof == nil || proof.LeftProof.Index+1 == proof.RightProof.Index
+}
+
+// search returns the index of the leaf, or the index it would be inserted at and false
+func (m *MerkleSet[T]) search(leaf []byte) (int, bool) {
+	i := sort.Search(len(m.leaves), func(i int) bool {
+		return bytes.Compare(m.leaves[i], leaf) >= 0
+	})
+	return i, i < len(m.leaves) && bytes.Equal(m.leaves[i], leaf)
+}
+
+// proof returns the audit path of the leaf at index i
+func (m *MerkleSet[T]) proof(i int) *InclusionProof {
+	proof := &InclusionProof{Index: i}
+	for _, level := range m.levels[:len(m.levels)-1] {
+		// a promoted node has no sibling on this level
+		if sibling := i ^ 1; sibling < len(level) {
+			proof.Path = append(proof.Path, level[sibling])
+		}
+		i >>= 1
+	}
+	return proof
+}
+
+func leafHash(leaf []byte) [sha256.Size]byte {
+	return sha256.Sum256(append([]byte{0}, leaf...))
+}
+
+func nodeHash(left, right [sha256.Size]byte) [sha256.Size]byte {
+	buf := make([]byte, 0, 1+2*sha256.Size)
+	buf = append(buf, 1)
+	buf = append(buf, left[:]...)
+	buf = append(buf, right[:]...)
+	return sha256.Sum256(buf)
+}
@@ -0,0 +1,147 @@
+package set
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+)
+
+func identity(b string) []byte {
+	return []byte(b)
+}
+
+// rfc6962Leaves are the leaves of the test vectors of the certificate transparency reference implementation
+var rfc6962Leaves = []string{"", "\x00", "\x10", "\x20\x21", "\x30\x31", "\x40\x41\x42\x43",
+	"\x50\x51\x52\x53\x54\x55\x56\x57", "\x60\x61\x62\x63\x64\x65\x66\x67\x68\x69\x6a\x6b\x6c\x6d\x6e\x6f"}
+
+func TestMerkleSet_Root(t *testing.T) {
+	for n, want := range map[int]string{
+		0: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+		1: "6e340b9cffb37a989ca544e6bb780a2c78901d3fb33738768511a30617afa01d",
+		5: "4e3bbb1f7b478dcfe71fb631631519a3bca12c9aefca1612bfce4c13a86264d4",
+		8: "5dc9da79a70659a9ad559cb701ded9a2ab9d823aad2f4960cfe370eff4604328",
+	} {
+		m := NewMerkleSet(NewSetFromSlice(rfc6962Leaves[:n]), identity)
+		root := m.Root()
+		if got := hex.EncodeToString(root[:]); got != want {
+			t.Errorf("Expected the root of %d leaves to be %s, got %s", n, want, got)
+		}
+		if m.Len() != n {
+			t.Errorf("Expected %d leaves, got %d", n, m.Len())
+		}
+	}
+}
+
+func encodeUint(i int) []byte {
+	return binary.BigEndian.AppendUint32(nil, uint32(i))
+}
+
+func TestMerkleSet_Inclusion(t *testing.T) {
+	for n := 1; n <= 33; n++ {
+		// the even numbers, so every odd number is missing
+		s := NewSet[int]()
+		for i := 0; i < n; i++ {
+			s.Add(2 * i)
+		}
+		m := NewMerkleSet(s, encodeUint)
+		root := m.Root()
+		for i := 0; i < n; i++ {
+			proof, ok := m.ProveInclusion(2 * i)
+			if !ok || !m.Contains(2*i) {
+				t.Fatalf("Expected %d in the set of %d leaves", 2*i, n)
+			}
+			if !VerifyInclusion(root, n, encodeUint(2*i), proof) {
+				t.Errorf("Expected the proof of %d in %d leaves to verify", 2*i, n)
+			}
+			if VerifyInclusion(root, n, encodeUint(2*i+1), proof) {
+				t.Errorf("Expected the proof of %d not to verify for %d", 2*i, 2*i+1)
+			}
+			wrong := *proof
+			wrong.Index ^= 1
+			if wrong.Index < n && VerifyInclusion(root, n, encodeUint(2*i), &wrong) {
+				t.Errorf("Expected the proof of %d with the wrong index not to verify", 2*i)
+			}
+			// a smaller tree can't make a leaf look like the last one
+			if i < n-1 && VerifyInclusion(root, i+1, encodeUint(2*i), proof) {
+				t.Errorf("Expected the proof of %d not to verify for a tree of %d leaves", 2*i, i+1)
+			}
+		}
+		if _, ok := m.ProveInclusion(1); ok {
+			t.Error("MerkleSet.ProveInclusion() returned true for a missing element")
+		}
+	}
+	if VerifyInclusion(sha256.Sum256(nil), 0, nil, nil) || VerifyInclusion(sha256.Sum256(nil), 0, nil, &InclusionProof{Index: 0}) {
+		t.Error("Expected invalid proofs not to verify")
+	}
+	// a path longer than the tree is deep
+	m := NewMerkleSet(NewSetFromSlice([]int{1, 2}), encodeUint)
+	proof, _ := m.ProveInclusion(1)
+	proof.Path = append(proof.Path, proof.Path[0])
+	if VerifyInclusion(m.Root(), 2, encodeUint(1), proof) {
+		t.Error("Expected a proof with extra hashes not to verify")
+	}
+}
+
+func TestMerkleSet_Exclusion(t *testing.T) {
+	for n := 0; n <= 33; n++ {
+		s := NewSet[int]()
+		for i := 0; i < n; i++ {
+			s.Add(2*i + 1)
+		}
+		m := NewMerkleSet(s, encodeUint)
+		root := m.Root()
+		for i := 0; i <= n; i++ {
+			proof, ok := m.ProveExclusion(2 * i)
+			if !ok {
+				t.Fatalf("Expected %d not to be in the set of %d leaves", 2*i, n)
+			}
+			if !VerifyExclusion(root, n, encodeUint(2*i), proof) {
+				t.Errorf("Expected the exclusion proof of %d in %d leaves to verify", 2*i, n)
+			}
+			// 2*i+1 is a leaf unless 2*i sorts after all of them
+			if i < n && VerifyExclusion(root, n, encodeUint(2*i+1), proof) {
+				t.Errorf("Expected the exclusion proof of %d not to verify for %d", 2*i, 2*i+1)
+			}
+		}
+		if _, ok := m.ProveExclusion(1); n > 0 && ok {
+			t.Error("MerkleSet.ProveExclusion() returned true for an element in the set")
+		}
+	}
+}
+
+func TestMerkleSet_ForgedExclusion(t *testing.T) {
+	m := NewMerkleSet(NewSetFromSlice([]int{1, 3, 5, 7}), encodeUint)
+	root := m.Root()
+	proof1, _ := m.ProveInclusion(1)
+	proof3, _ := m.ProveInclusion(3)
+	proof5, _ := m.ProveInclusion(5)
+	for name, forged := range map[string]*ExclusionProof{
+		"nil":           nil,
+		"no neighbours": {},
+		"not adjacent":  {Left: encodeUint(1), LeftProof: proof1, Right: encodeUint(5), RightProof: proof5},
+		"not the last":  {Left: encodeUint(3), LeftProof: proof3},
+		"not the first": {Right: encodeUint(5), RightProof: proof5},
+		"wrong leaf":    {Left: encodeUint(2), LeftProof: proof3, Right: encodeUint(5), RightProof: proof5},
+		"wrong order":   {Left: encodeUint(5), LeftProof: proof5, Right: encodeUint(3), RightProof: proof3},
+	} {
+		// 4 is between 3 and 5, and a forged proof of it must fail
+		if VerifyExclusion(root, 4, encodeUint(4), forged) {
+			t.Errorf("Expected the %s proof not to verify", name)
+		}
+	}
+	// claiming a smaller tree doesn't make 3 the last leaf
+	if VerifyExclusion(root, 2, encodeUint(4), &ExclusionProof{Left: encodeUint(3), LeftProof: proof3}) {
+		t.Error("Expected a proof for the wrong tree size not to verify")
+	}
+	if VerifyExclusion(root, 0, nil, &ExclusionProof{}) || VerifyExclusion(root, 0, nil, &ExclusionProof{LeftProof: proof1}) {
+		t.Error("Expected an empty proof not to verify against a non-empty tree")
+	}
+}
+
+func TestMerkleSet_DuplicateEncodings(t *testing.T) {
+	m := NewMerkleSet(NewSetFromSlice([]int{1, 2, 3}), func(int) []byte { return []byte("same") })
+	if m.Len() != 1 {
+		t.Errorf("Expected elements with the same encoding to share a leaf, got %d leaves", m.Len())
+	}
+}
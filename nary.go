@@ -0,0 +1,134 @@
+package set
+
+// Union returns the values that are in any of the sets as a new set
+// It builds the result in one pass, without the intermediate sets of chained Union calls
+// The result keeps the hash check if any of the sets has it
+func Union[T comparable](sets ...*Set[T]) *Set[T] {
+	return derivedFrom(sets, unionMaps(mapsOf(sets)))
+}
+
+// Intersection returns the values that are in every one of the sets as a new set
+// It iterates over the smallest set and returns early if any set is empty
+// The intersection of no sets is the empty set, and the result keeps the hash check if any of the sets has it
+func Intersection[T comparable](sets ...*Set[T]) *Set[T] {
+	return derivedFrom(sets, intersectMaps(mapsOf(sets)))
+}
+
+// Difference returns the values in base that are in none of the others as a new set with the options of base
+func Difference[T comparable](base *Set[T], others ...*Set[T]) *Set[T] {
+	return base.derived(differenceMaps(base.m, mapsOf(others)))
+}
+
+// ThreadSafeUnion returns the values that are in any of the sets as a new set
+// Every set is locked for the whole operation, in a consistent order
+func ThreadSafeUnion[T comparable](sets ...*ThreadSafeSet[T]) *ThreadSafeSet[T] {
+	unlock := lockAll(sets...)
+	defer unlock()
+	return &ThreadSafeSet[T]{m: unionMaps(threadSafeMapsOf(sets))}
+}
+
+// ThreadSafeIntersection returns the values that are in every one of the sets as a new set
+// Every set is locked for the whole operation, in a consistent order
+// The intersection of no sets is the empty set
+func ThreadSafeIntersection[T comparable](sets ...*ThreadSafeSet[T]) *ThreadSafeSet[T] {
+	unlock := lockAll(sets...)
+	defer unlock()
+	return &ThreadSafeSet[T]{m: intersectMaps(threadSafeMapsOf(sets))}
+}
+
+// ThreadSafeDifference returns the values in base that are in none of the others as a new set
+// Every set is locked for the whole operation, in a consistent order
+func ThreadSafeDifference[T comparable](base *ThreadSafeSet[T], others ...*ThreadSafeSet[T]) *ThreadSafeSet[T] {
+	unlock := lockAll(append([]*ThreadSafeSet[T]{base}, others...)...)
+	defer unlock()
+	return &ThreadSafeSet[T]{m: differenceMaps(base.m, threadSafeMapsOf(others))}
+}
+
+// derivedFrom returns a new set of the elements of m that keeps the hash check if any of the sets has it
+func derivedFrom[T comparable](sets []*Set[T], m map[T]struct{}) *Set[T] {
+	for _, s := range sets {
+		if s.checkHashable {
+			return s.derived(m)
+		}
+	}
+	return &Set[T]{m: m}
+}
+
+func mapsOf[T comparable](sets []*Set[T]) []map[T]struct{} {
+	maps := make([]map[T]struct{}, len(sets))
+	for i, s := range sets {
+		maps[i] = s.m
+	}
+	return maps
+}
+
+func threadSafeMapsOf[T comparable](sets []*ThreadSafeSet[T]) []map[T]struct{} {
+	maps := make([]map[T]struct{}, len(sets))
+	for i, s := range sets {
+		maps[i] = s.m
+	}
+	return maps
+}
+
+func unionMaps[T comparable](maps []map[T]struct{}) map[T]struct{} {
+	// the union is at least as large as the largest set
+	size := 0
+	for _, m := range maps {
+		if len(m) > size {
+			size = len(m)
+		}
+	}
+	union := make(map[T]struct{}, size)
+	for _, m := range maps {
+		for k := range m {
+			union[k] = struct{}{}
+		}
+	}
+	return union
+}
+
+func intersectMaps[T comparable](maps []map[T]struct{}) map[T]struct{} {
+	if len(maps) == 0 {
+		return make(map[T]struct{})
+	}
+	smallest := 0
+	for i, m := range maps {
+		if len(m) < len(maps[smallest]) {
+			smallest = i
+		}
+	}
+	intersection := make(map[T]struct{}, len(maps[smallest]))
+	if len(maps[smallest]) == 0 {
+		return intersection
+	}
+	others := make([]map[T]struct{}, 0, len(maps)-1)
+	for i, m := range maps {
+		if i != smallest {
+			others = append(others, m)
+		}
+	}
+outer:
+	for k := range maps[smallest] {
+		for _, m := range others {
+			if _, ok := m[k]; !ok {
+				continue outer
+			}
+		}
+		intersection[k] = struct{}{}
+	}
+	return intersection
+}
+
+func differenceMaps[T comparable](base map[T]struct{}, others []map[T]struct{}) map[T]struct{} {
+	difference := make(map[T]struct{}, len(base))
+outer:
+	for k := range base {
+		for _, m := range others {
+			if _, ok := m[k]; ok {
+				continue outer
+			}
+		}
+		difference[k] = struct{}{}
+	}
+	return difference
+}
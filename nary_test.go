@@ -0,0 +1,121 @@
+package set
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/drkennetz/set/settest"
+)
+
+func TestUnion(t *testing.T) {
+	a := NewSetFromSlice([]int{1, 2})
+	b := NewSetFromSlice([]int{2, 3})
+	c := NewSetFromSlice([]int{5})
+	settest.AssertEqual[int](t, Union(a, b, c), NewSetFromSlice([]int{1, 2, 3, 5}))
+	settest.AssertEqual[int](t, Union(a), a)
+	if !Union[int]().IsEmpty() {
+		t.Error("Union() of no sets is not empty")
+	}
+	settest.AssertEqual[int](t, a, NewSetFromSlice([]int{1, 2}))
+}
+
+func TestIntersection(t *testing.T) {
+	a := NewSetFromSlice([]int{1, 2, 3, 4})
+	b := NewSetFromSlice([]int{2, 3, 4})
+	c := NewSetFromSlice([]int{3, 4, 5})
+	settest.AssertEqual[int](t, Intersection(a, b, c), NewSetFromSlice([]int{3, 4}))
+	settest.AssertEqual[int](t, Intersection(a, a), a)
+	if !Intersection(a, NewSet[int](), b).IsEmpty() {
+		t.Error("Intersection() with an empty set is not empty")
+	}
+	if !Intersection[int]().IsEmpty() {
+		t.Error("Intersection() of no sets is not empty")
+	}
+}
+
+func TestDifference(t *testing.T) {
+	base := NewSetFromSlice([]int{1, 2, 3, 4, 5})
+	settest.AssertEqual[int](t, Difference(base, NewSetFromSlice([]int{1}), NewSetFromSlice([]int{4, 6})), NewSetFromSlice([]int{2, 3, 5}))
+	settest.AssertEqual[int](t, Difference(base), base)
+	if !Difference(base, base).IsEmpty() {
+		t.Error("Difference() of a set with itself is not empty")
+	}
+}
+
+// go test -race -run TestThreadSafeNary .
+func TestThreadSafeNary(t *testing.T) {
+	a := NewThreadSafeSetFromSlice([]int{1, 2, 3})
+	b := NewThreadSafeSetFromSlice([]int{2, 3, 4})
+	c := NewThreadSafeSetFromSlice([]int{3, 4, 5})
+	var wg sync.WaitGroup
+	wg.Add(30)
+	for i := 0; i < 10; i++ {
+		// the inputs are passed in different orders, which must not deadlock
+		go func() {
+			defer wg.Done()
+			settest.AssertEqual[int](t, ThreadSafeUnion(a, b, c), NewSetFromSlice([]int{1, 2, 3, 4, 5}))
+		}()
+		go func() {
+			defer wg.Done()
+			settest.AssertEqual[int](t, ThreadSafeIntersection(c, b, a, a), NewSetFromSlice([]int{3}))
+		}()
+		go func() {
+			defer wg.Done()
+			settest.AssertEqual[int](t, ThreadSafeDifference(b, c, a), NewSet[int]())
+			settest.AssertEqual[int](t, ThreadSafeDifference(c, a), NewSetFromSlice([]int{4, 5}))
+		}()
+	}
+	wg.Wait()
+	if !ThreadSafeIntersection[int]().IsEmpty() || !ThreadSafeUnion[int]().IsEmpty() {
+		t.Error("Expected the union and intersection of no sets to be empty")
+	}
+}
+
+// go test -race -run TestTSSOppositeLockOrder .
+func TestTSSOppositeLockOrder(t *testing.T) {
+	a := NewThreadSafeSetFromSlice([]int{1, 2})
+	b := NewThreadSafeSetFromSlice([]int{2, 3})
+	var wg sync.WaitGroup
+	wg.Add(100)
+	for i := 0; i < 50; i++ {
+		go func() {
+			defer wg.Done()
+			a.Union(b)
+		}()
+		go func() {
+			defer wg.Done()
+			b.Intersection(a)
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkUnion_Chained(b *testing.B) {
+	sets := benchmarkSets()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		u := NewSet[int]()
+		for _, s := range sets {
+			u = u.Union(s)
+		}
+	}
+}
+
+func BenchmarkUnion_Variadic(b *testing.B) {
+	sets := benchmarkSets()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Union(sets...)
+	}
+}
+
+func benchmarkSets() []*Set[int] {
+	sets := make([]*Set[int], 20)
+	for i := range sets {
+		sets[i] = NewSet[int]()
+		for j := 0; j < 1000; j++ {
+			sets[i].Add(i*500 + j)
+		}
+	}
+	return sets
+}
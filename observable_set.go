@@ -0,0 +1,390 @@
+package set
+
+import (
+	"fmt"
+	"sync"
+)
+
+// EventKind is the kind of change an Event describes
+type EventKind int
+
+const (
+	// Added means the elements were added to the set
+	Added EventKind = iota + 1
+	// Removed means the elements were removed from the set
+	Removed
+	// Cleared means the set was cleared, the event holds the elements it contained
+	Cleared
+)
+
+// String returns the name of the event kind
+func (k EventKind) String() string {
+	switch k {
+	case Added:
+		return "Added"
+	case Removed:
+		return "Removed"
+	case Cleared:
+		return "Cleared"
+	}
+	return fmt.Sprintf("EventKind(%d)", int(k))
+}
+
+// Event describes a change to an ObservableSet
+// Bulk operations are batched into a single event holding every element they changed
+type Event[T comparable] struct {
+	Kind     EventKind
+	Elements []T
+}
+
+// SlowPolicy decides what happens to events for a channel subscriber whose buffer is full
+type SlowPolicy int
+
+const (
+	// SlowDrop drops the events that don't fit in the subscriber's buffer
+	SlowDrop SlowPolicy = iota
+	// SlowBlock makes the methods that change the set wait until the subscriber has room for their event
+	// The change itself is made first, so readers see it at once and never wait for the subscriber
+	SlowBlock
+	// SlowCoalesce merges the events the subscriber hasn't received yet into one Removed
+	// and one Added event describing the net change, so no change is lost and the set never blocks
+	SlowCoalesce
+)
+
+// observed is the part of the Set and ThreadSafeSet API an ObservableSet wraps
+type observed[T comparable] interface {
+	Add(e T)
+	Remove(e T)
+	Contains(e T) bool
+	Len() int
+	Clear()
+	ToSlice() []T
+}
+
+// ObservableSet wraps a Set or ThreadSafeSet and notifies subscribers of every change.
+// It is safe for concurrent use, whichever set it wraps, and events are delivered in
+// the order the changes were made. The wrapped set must only be changed through the
+// ObservableSet.
+type ObservableSet[T comparable] struct {
+	s    observed[T]
+	l    sync.Mutex // guards s, subs and next
+	subs []subscriber[T]
+	next uint64 // the sequence number of the next event
+	// events are delivered in sequence number order, turn is the one whose turn it is
+	emit    sync.Mutex // guards turn
+	turn    uint64
+	emitted *sync.Cond // signalled when turn moves on
+}
+
+// subscriber receives the events of an ObservableSet
+type subscriber[T comparable] interface {
+	deliver(ev Event[T])
+	close()
+}
+
+// NewObservableSet returns an observable set wrapping s
+func NewObservableSet[T comparable](s *Set[T]) *ObservableSet[T] {
+	return newObservableSet[T](s)
+}
+
+// NewObservableThreadSafeSet returns an observable set wrapping s
+func NewObservableThreadSafeSet[T comparable](s *ThreadSafeSet[T]) *ObservableSet[T] {
+	return newObservableSet[T](s)
+}
+
+func newObservableSet[T comparable](s observed[T]) *ObservableSet[T] {
+	o := &ObservableSet[T]{s: s}
+	o.emitted = sync.NewCond(&o.emit)
+	return o
+}
+
+// Add adds an element to the set and publishes an Added event if it was not in the set
+func (o *ObservableSet[T]) Add(e T) {
+	o.AddAll(e)
+}
+
+// AddAll adds the elements to the set and publishes a single Added event for the ones that were not in the set
+func (o *ObservableSet[T]) AddAll(elems ...T) {
+	o.l.Lock()
+	var added []T
+	for _, e := range elems {
+		if !o.s.Contains(e) {
+			o.s.Add(e)
+			added = append(added, e)
+		}
+	}
+	o.publish(Event[T]{Kind: Added, Elements: added})
+}
+
+// Remove removes an element from the set and publishes a Removed event if it was in the set
+func (o *ObservableSet[T]) Remove(e T) {
+	o.RemoveAll(e)
+}
+
+// RemoveAll removes the elements from the set and publishes a single Removed event for the ones that were in the set
+func (o *ObservableSet[T]) RemoveAll(elems ...T) {
+	o.l.Lock()
+	var removed []T
+	for _, e := range elems {
+		if o.s.Contains(e) {
+			o.s.Remove(e)
+			removed = append(removed, e)
+		}
+	}
+	o.publish(Event[T]{Kind: Removed, Elements: removed})
+}
+
+// Clear removes all elements from the set and publishes a Cleared event holding them if the set was not empty
+func (o *ObservableSet[T]) Clear() {
+	o.l.Lock()
+	cleared := o.s.ToSlice()
+	o.s.Clear()
+	o.publish(Event[T]{Kind: Cleared, Elements: cleared})
+}
+
+// Contains returns true if the set contains the element
+func (o *ObservableSet[T]) Contains(e T) bool {
+	o.l.Lock()
+	defer o.l.Unlock()
+	return o.s.Contains(e)
+}
+
+// Len returns the number of elements in the set
+func (o *ObservableSet[T]) Len() int {
+	o.l.Lock()
+	defer o.l.Unlock()
+	return o.s.Len()
+}
+
+// IsEmpty returns true if the set is empty
+func (o *ObservableSet[T]) IsEmpty() bool {
+	return o.Len() == 0
+}
+
+// ToSlice returns a slice of the elements in the set
+func (o *ObservableSet[T]) ToSlice() []T {
+	o.l.Lock()
+	defer o.l.Unlock()
+	return o.s.ToSlice()
+}
+
+// String returns a string representation of the set
+func (o *ObservableSet[T]) String() string {
+	return fmt.Sprintf("%v", o.ToSlice())
+}
+
+// Subscribe calls fn with every subsequent event and returns a function that unsubscribes it.
+// fn is called synchronously, in order, after the change is made. It may read the set
+// but must not change it.
+func (o *ObservableSet[T]) Subscribe(fn func(Event[T])) (unsubscribe func()) {
+	return o.subscribe(&callbackSubscriber[T]{fn: fn})
+}
+
+// SubscribeChan returns a channel with the given buffer size that receives every subsequent
+// event, and a function that unsubscribes and closes the channel.
+// policy decides what happens when the subscriber falls behind and the buffer is full.
+func (o *ObservableSet[T]) SubscribeChan(buffer int, policy SlowPolicy) (<-chan Event[T], func()) {
+	ch := make(chan Event[T], buffer)
+	done := make(chan struct{})
+	var sub subscriber[T]
+	switch policy {
+	case SlowBlock:
+		sub = &chanSubscriber[T]{ch: ch, done: done, block: true}
+	case SlowCoalesce:
+		c := &coalescingSubscriber[T]{ch: ch, done: done, signal: make(chan struct{}, 1)}
+		go c.forward()
+		sub = c
+	default:
+		sub = &chanSubscriber[T]{ch: ch, done: done}
+	}
+	return ch, o.subscribe(sub)
+}
+
+// subscribe adds sub to the subscribers and returns a function that removes it
+func (o *ObservableSet[T]) subscribe(sub subscriber[T]) func() {
+	o.l.Lock()
+	defer o.l.Unlock()
+	// subs is copied on write so publish can deliver to a snapshot without copying it
+	subs := make([]subscriber[T], len(o.subs), len(o.subs)+1)
+	copy(subs, o.subs)
+	o.subs = append(subs, sub)
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			// closing first releases a delivery blocked on the subscriber
+			sub.close()
+			o.l.Lock()
+			subs := make([]subscriber[T], 0, len(o.subs))
+			for _, s := range o.subs {
+				if s != sub {
+					subs = append(subs, s)
+				}
+			}
+			o.subs = subs
+			o.l.Unlock()
+		})
+	}
+}
+
+// publish delivers ev to every subscriber, o.l must be held and is released
+// The event takes a sequence number under o.l and waits for its turn without it, so events are
+// delivered in the order the changes were made and a blocked subscriber doesn't block readers
+func (o *ObservableSet[T]) publish(ev Event[T]) {
+	if len(ev.Elements) == 0 {
+		o.l.Unlock()
+		return
+	}
+	subs, seq := o.subs, o.next
+	o.next++
+	o.l.Unlock()
+	o.emit.Lock()
+	for o.turn != seq {
+		o.emitted.Wait()
+	}
+	o.emit.Unlock()
+	defer func() {
+		o.emit.Lock()
+		o.turn++
+		o.emit.Unlock()
+		o.emitted.Broadcast()
+	}()
+	for _, sub := range subs {
+		sub.deliver(ev)
+	}
+}
+
+// callbackSubscriber calls a function with every event
+// It is used through a pointer because subscribers are compared when unsubscribing
+type callbackSubscriber[T comparable] struct {
+	fn func(Event[T])
+}
+
+func (c *callbackSubscriber[T]) deliver(ev Event[T]) {
+	c.fn(ev)
+}
+
+func (c *callbackSubscriber[T]) close() {}
+
+// chanSubscriber sends every event to a channel, dropping or blocking when it is full
+type chanSubscriber[T comparable] struct {
+	ch    chan Event[T]
+	done  chan struct{}
+	block bool
+	mu    sync.Mutex // guards ch against being closed during a send
+}
+
+func (c *chanSubscriber[T]) deliver(ev Event[T]) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	select {
+	case <-c.done:
+		return
+	default:
+	}
+	if !c.block {
+		select {
+		case c.ch <- ev:
+		default:
+		}
+		return
+	}
+	select {
+	case c.ch <- ev:
+	case <-c.done:
+	}
+}
+
+func (c *chanSubscriber[T]) close() {
+	close(c.done)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	close(c.ch)
+}
+
+// coalescingSubscriber merges the events a slow subscriber hasn't received yet
+// A goroutine forwards the merged events to the channel so deliver never blocks
+type coalescingSubscriber[T comparable] struct {
+	ch      chan Event[T]
+	done    chan struct{}
+	signal  chan struct{}
+	mu      sync.Mutex // guards added and removed
+	added   map[T]struct{}
+	removed map[T]struct{}
+}
+
+func (c *coalescingSubscriber[T]) deliver(ev Event[T]) {
+	c.mu.Lock()
+	if c.added == nil {
+		c.added = make(map[T]struct{})
+		c.removed = make(map[T]struct{})
+	}
+	// events only describe real changes, so an element added and then removed
+	// (or removed and then added) is back where it started
+	for _, e := range ev.Elements {
+		if ev.Kind == Added {
+			if _, ok := c.removed[e]; ok {
+				delete(c.removed, e)
+			} else {
+				c.added[e] = struct{}{}
+			}
+		} else {
+			if _, ok := c.added[e]; ok {
+				delete(c.added, e)
+			} else {
+				c.removed[e] = struct{}{}
+			}
+		}
+	}
+	c.mu.Unlock()
+	select {
+	case c.signal <- struct{}{}:
+	default:
+	}
+}
+
+func (c *coalescingSubscriber[T]) close() {
+	close(c.done)
+}
+
+// forward sends the merged events to the channel until the subscriber is closed
+func (c *coalescingSubscriber[T]) forward() {
+	defer close(c.ch)
+	for {
+		select {
+		case <-c.signal:
+		case <-c.done:
+			return
+		}
+		for _, ev := range c.take() {
+			select {
+			case c.ch <- ev:
+			case <-c.done:
+				return
+			}
+		}
+	}
+}
+
+// take returns the merged events and resets them
+func (c *coalescingSubscriber[T]) take() []Event[T] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var events []Event[T]
+	if len(c.removed) > 0 {
+		events = append(events, Event[T]{Kind: Removed, Elements: keys(c.removed)})
+	}
+	if len(c.added) > 0 {
+		events = append(events, Event[T]{Kind: Added, Elements: keys(c.added)})
+	}
+	c.added, c.removed = nil, nil
+	return events
+}
+
+// keys returns the keys of m as a slice
+func keys[T comparable](m map[T]struct{}) []T {
+	slice := make([]T, 0, len(m))
+	for k := range m {
+		slice = append(slice, k)
+	}
+	return slice
+}
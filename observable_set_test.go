@@ -0,0 +1,233 @@
+package set
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/drkennetz/set/settest"
+)
+
+// eventSet returns the elements of an event as a set
+func eventSet[T comparable](ev Event[T]) *Set[T] {
+	return NewSetFromSlice(ev.Elements)
+}
+
+func TestObservableSet_Subscribe(t *testing.T) {
+	o := NewObservableSet(NewSet[int]())
+	var events []Event[int]
+	unsubscribe := o.Subscribe(func(ev Event[int]) {
+		// callbacks may read the set
+		if ev.Kind == Added && !o.Contains(ev.Elements[0]) {
+			t.Error("ObservableSet published an event before making the change")
+		}
+		events = append(events, ev)
+	})
+	o.Add(1)
+	o.Add(1)
+	o.AddAll(2, 3, 2, 1)
+	o.Remove(4)
+	o.RemoveAll(1, 2, 5)
+	o.Clear()
+	o.Clear()
+	unsubscribe()
+	unsubscribe()
+	o.Add(6)
+
+	want := []struct {
+		kind  EventKind
+		elems []int
+	}{
+		{Added, []int{1}},
+		{Added, []int{2, 3}},
+		{Removed, []int{1, 2}},
+		{Cleared, []int{3}},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("Expected %d events, got %v", len(want), events)
+	}
+	for i, w := range want {
+		if events[i].Kind != w.kind {
+			t.Errorf("Expected event %d to be %v, got %v", i, w.kind, events[i].Kind)
+		}
+		settest.AssertEqual[int](t, eventSet(events[i]), NewSetFromSlice(w.elems))
+	}
+	if !o.Contains(6) || o.Len() != 1 || o.IsEmpty() {
+		t.Errorf("Expected {6}, got %v", o)
+	}
+	settest.AssertEqual[int](t, o, NewSetFromSlice([]int{6}))
+}
+
+func TestEventKind_String(t *testing.T) {
+	if Added.String() != "Added" || Removed.String() != "Removed" || Cleared.String() != "Cleared" {
+		t.Error("EventKind.String() returned the wrong name")
+	}
+	if EventKind(9).String() != "EventKind(9)" {
+		t.Errorf("Expected EventKind(9), got %s", EventKind(9))
+	}
+}
+
+func TestObservableSet_SubscribeChanDrop(t *testing.T) {
+	o := NewObservableThreadSafeSet(NewThreadSafeSet[string]())
+	ch, unsubscribe := o.SubscribeChan(1, SlowDrop)
+	o.Add("a")
+	o.Add("b")
+	ev := <-ch
+	if ev.Kind != Added || ev.Elements[0] != "a" {
+		t.Errorf("Expected the first event, got %v", ev)
+	}
+	select {
+	case ev := <-ch:
+		t.Errorf("Expected the second event to be dropped, got %v", ev)
+	default:
+	}
+	unsubscribe()
+	if _, ok := <-ch; ok {
+		t.Error("Expected the channel to be closed after unsubscribing")
+	}
+	o.Add("c")
+}
+
+// go test -race -run TestObservableSet_SubscribeChanBlock .
+func TestObservableSet_SubscribeChanBlock(t *testing.T) {
+	o := NewObservableSet(NewSet[int]())
+	ch, unsubscribe := o.SubscribeChan(0, SlowBlock)
+	done := make(chan struct{})
+	go func() {
+		o.Add(1)
+		o.Add(2)
+		close(done)
+	}()
+	for i := 1; i <= 2; i++ {
+		if ev := <-ch; ev.Elements[0] != i {
+			t.Errorf("Expected %d, got %v", i, ev)
+		}
+	}
+	<-done
+	// unsubscribing must release a publisher blocked on the subscriber
+	blocked := make(chan struct{})
+	go func() {
+		o.Add(3)
+		close(blocked)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	unsubscribe()
+	<-blocked
+}
+
+// go test -race -run TestObservableSet_UnsubscribeBlocked .
+func TestObservableSet_UnsubscribeBlocked(t *testing.T) {
+	o := NewObservableSet(NewSet[int]())
+	// callbacks run in subscription order, so this one runs just before the blocking subscriber
+	delivering := make(chan struct{})
+	o.Subscribe(func(ev Event[int]) {
+		if ev.Elements[0] == 1 {
+			close(delivering)
+		}
+	})
+	_, unsubscribe := o.SubscribeChan(0, SlowBlock)
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		o.Add(1)
+	}()
+	<-delivering
+	go func() {
+		defer wg.Done()
+		o.Add(2)
+	}()
+	// the second Add makes its change and waits for the first event, readers must not wait with it
+	deadline := time.Now().Add(time.Second)
+	for o.Len() != 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the second change to be made, got %v", o)
+		}
+		runtime.Gosched()
+	}
+	done := make(chan struct{})
+	go func() {
+		unsubscribe()
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected unsubscribing to release the blocked writers")
+	}
+	o.Add(3)
+}
+
+// go test -race -run TestObservableSet_SubscribeChanCoalesce .
+func TestObservableSet_SubscribeChanCoalesce(t *testing.T) {
+	o := NewObservableSet(NewSetFromSlice([]int{1, 2}))
+	ch, unsubscribe := o.SubscribeChan(0, SlowCoalesce)
+	defer unsubscribe()
+	// nobody is receiving, so these changes are merged
+	o.Add(3)
+	o.Remove(3)
+	o.Remove(1)
+	o.Add(1)
+	o.AddAll(4, 5)
+	o.Remove(2)
+	// the forwarder may already hold the first change, so apply the events to a mirror
+	mirror := NewSetFromSlice([]int{1, 2})
+	events := 0
+	deadline := time.After(time.Second)
+	for !mirror.IsEqual(NewSetFromSlice(o.ToSlice())) {
+		select {
+		case ev := <-ch:
+			events++
+			if ev.Kind == Added {
+				mirror = mirror.Union(eventSet(ev))
+			} else {
+				mirror = mirror.Difference(eventSet(ev))
+			}
+		case <-deadline:
+			t.Fatalf("Timed out waiting for events, got %v", mirror)
+		}
+	}
+	if events > 4 {
+		t.Errorf("Expected 6 changes to be coalesced into at most 4 events, got %d", events)
+	}
+	settest.AssertEqual[int](t, mirror, NewSetFromSlice([]int{1, 4, 5}))
+	o.Clear()
+	ev := <-ch
+	if ev.Kind != Removed {
+		t.Errorf("Expected a cleared set to coalesce into a Removed event, got %v", ev)
+	}
+	settest.AssertEqual[int](t, eventSet(ev), NewSetFromSlice([]int{1, 4, 5}))
+}
+
+// go test -race -run TestObservableSet_Concurrent .
+func TestObservableSet_Concurrent(t *testing.T) {
+	o := NewObservableThreadSafeSet(NewThreadSafeSet[int]())
+	var mu sync.Mutex
+	mirror := NewSet[int]()
+	o.Subscribe(func(ev Event[int]) {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, e := range ev.Elements {
+			if ev.Kind == Added {
+				mirror.Add(e)
+			} else {
+				mirror.Remove(e)
+			}
+		}
+	})
+	var wg sync.WaitGroup
+	wg.Add(20)
+	for i := 0; i < 20; i++ {
+		go func(i int) {
+			defer wg.Done()
+			o.AddAll(i, i+1, i+2)
+			o.Remove(i + 1)
+		}(i)
+	}
+	wg.Wait()
+	mu.Lock()
+	defer mu.Unlock()
+	settest.AssertEqual[int](t, mirror, o)
+}
@@ -0,0 +1,260 @@
+package set
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// DefaultSerialThreshold is the number of elements below which the parallel operations run serially
+const DefaultSerialThreshold = 10000
+
+// cancelCheckInterval is how many elements a worker handles between checks for cancellation
+const cancelCheckInterval = 1024
+
+// parallelConfig holds the options of the parallel operations
+type parallelConfig struct {
+	workers   int
+	threshold int
+}
+
+// ParallelOption configures a parallel operation
+type ParallelOption func(*parallelConfig)
+
+// WithWorkers sets the number of goroutines a parallel operation uses, GOMAXPROCS by default
+func WithWorkers(n int) ParallelOption {
+	return func(cfg *parallelConfig) {
+		cfg.workers = n
+	}
+}
+
+// WithSerialThreshold sets the number of elements below which a parallel operation runs serially
+func WithSerialThreshold(n int) ParallelOption {
+	return func(cfg *parallelConfig) {
+		cfg.threshold = n
+	}
+}
+
+func newParallelConfig(opts []ParallelOption) parallelConfig {
+	cfg := parallelConfig{
+		workers:   runtime.GOMAXPROCS(0),
+		threshold: DefaultSerialThreshold,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// ParallelFilter returns a new set containing only the elements that satisfy the predicate
+// The predicate is called from several goroutines at once, so it must be safe for concurrent use
+// It returns ctx.Err() if the context is done before the result is complete
+func (s *Set[T]) ParallelFilter(ctx context.Context, predicate func(T) bool, opts ...ParallelOption) (*Set[T], error) {
+	m, err := parallelFilter(ctx, s.m, predicate, newParallelConfig(opts))
+	if err != nil {
+		return nil, err
+	}
+	return s.derived(m), nil
+}
+
+// ParallelMap returns a new set containing the results of applying the function to each element
+// The function is called from several goroutines at once, so it must be safe for concurrent use
+// Like Map, a set created WithHashCheck leaves the unhashable results out
+// It returns ctx.Err() if the context is done before the result is complete
+func (s *Set[T]) ParallelMap(ctx context.Context, f func(T) T, opts ...ParallelOption) (*Set[T], error) {
+	m, err := parallelCollect(ctx, s.m, func(k T) (T, bool) {
+		v := f(k)
+		return v, !s.checkHashable || hashable(v) == nil
+	}, newParallelConfig(opts))
+	if err != nil {
+		return nil, err
+	}
+	return s.derived(m), nil
+}
+
+// ParallelUnion returns the union of two sets as a new set
+// The larger set is copied and the values of the smaller one it lacks are found in parallel
+// It returns ctx.Err() if the context is done before the result is complete
+func (s *Set[T]) ParallelUnion(ctx context.Context, s2 *Set[T], opts ...ParallelOption) (*Set[T], error) {
+	m, err := parallelUnion(ctx, s.m, s2.m, newParallelConfig(opts))
+	if err != nil {
+		return nil, err
+	}
+	return s.derived(m), nil
+}
+
+// ParallelIntersection returns the intersection of two sets as a new set
+// The values of the smaller set are checked against the larger one in parallel
+// It returns ctx.Err() if the context is done before the result is complete
+func (s *Set[T]) ParallelIntersection(ctx context.Context, s2 *Set[T], opts ...ParallelOption) (*Set[T], error) {
+	m, err := parallelIntersection(ctx, s.m, s2.m, newParallelConfig(opts))
+	if err != nil {
+		return nil, err
+	}
+	return s.derived(m), nil
+}
+
+// ParallelFilter returns a new set containing only the elements that pass the predicate
+// The set is locked for the whole operation and the predicate is called from several goroutines at once
+// It returns ctx.Err() if the context is done before the result is complete
+func (s *ThreadSafeSet[T]) ParallelFilter(ctx context.Context, predicate func(T) bool, opts ...ParallelOption) (*ThreadSafeSet[T], error) {
+	s.l.Lock()
+	defer s.l.Unlock()
+	m, err := parallelFilter(ctx, s.m, predicate, newParallelConfig(opts))
+	if err != nil {
+		return nil, err
+	}
+	return &ThreadSafeSet[T]{m: m}, nil
+}
+
+// ParallelMap returns a new set containing the results of applying the function to each element
+// The set is locked for the whole operation and the function is called from several goroutines at once
+// It returns ctx.Err() if the context is done before the result is complete
+func (s *ThreadSafeSet[T]) ParallelMap(ctx context.Context, fn func(T) T, opts ...ParallelOption) (*ThreadSafeSet[T], error) {
+	s.l.Lock()
+	defer s.l.Unlock()
+	m, err := parallelCollect(ctx, s.m, func(k T) (T, bool) {
+		return fn(k), true
+	}, newParallelConfig(opts))
+	if err != nil {
+		return nil, err
+	}
+	return &ThreadSafeSet[T]{m: m}, nil
+}
+
+// ParallelUnion returns the union of two sets as a new set
+// Both sets are locked for the whole operation
+// It returns ctx.Err() if the context is done before the result is complete
+func (s *ThreadSafeSet[T]) ParallelUnion(ctx context.Context, s2 *ThreadSafeSet[T], opts ...ParallelOption) (*ThreadSafeSet[T], error) {
+	unlock := lockAll(s, s2)
+	defer unlock()
+	m, err := parallelUnion(ctx, s.m, s2.m, newParallelConfig(opts))
+	if err != nil {
+		return nil, err
+	}
+	return &ThreadSafeSet[T]{m: m}, nil
+}
+
+// ParallelIntersection returns the intersection of two sets as a new set
+// Both sets are locked for the whole operation
+// It returns ctx.Err() if the context is done before the result is complete
+func (s *ThreadSafeSet[T]) ParallelIntersection(ctx context.Context, s2 *ThreadSafeSet[T], opts ...ParallelOption) (*ThreadSafeSet[T], error) {
+	unlock := lockAll(s, s2)
+	defer unlock()
+	m, err := parallelIntersection(ctx, s.m, s2.m, newParallelConfig(opts))
+	if err != nil {
+		return nil, err
+	}
+	return &ThreadSafeSet[T]{m: m}, nil
+}
+
+func parallelFilter[T comparable](ctx context.Context, m map[T]struct{}, predicate func(T) bool, cfg parallelConfig) (map[T]struct{}, error) {
+	return parallelCollect(ctx, m, func(k T) (T, bool) {
+		return k, predicate(k)
+	}, cfg)
+}
+
+func parallelUnion[T comparable](ctx context.Context, m, m2 map[T]struct{}, cfg parallelConfig) (map[T]struct{}, error) {
+	if len(m) < len(m2) {
+		m, m2 = m2, m
+	}
+	missing, err := parallelFilter(ctx, m2, func(k T) bool {
+		_, ok := m[k]
+		return !ok
+	}, cfg)
+	if err != nil {
+		return nil, err
+	}
+	union := make(map[T]struct{}, len(m)+len(missing))
+	for k := range m {
+		union[k] = struct{}{}
+	}
+	for k := range missing {
+		union[k] = struct{}{}
+	}
+	return union, nil
+}
+
+func parallelIntersection[T comparable](ctx context.Context, m, m2 map[T]struct{}, cfg parallelConfig) (map[T]struct{}, error) {
+	if len(m) > len(m2) {
+		m, m2 = m2, m
+	}
+	return parallelFilter(ctx, m, func(k T) bool {
+		_, ok := m2[k]
+		return ok
+	}, cfg)
+}
+
+// parallelCollect calls fn with every key of m and returns the set of values it emits.
+// The keys are split into one chunk per worker; each worker collects its results in a
+// slice, and the slices are merged into a map sized for all of them.
+// Below the serial threshold, or with a single worker, it runs on the calling goroutine.
+func parallelCollect[T, U comparable](ctx context.Context, m map[T]struct{}, fn func(T) (U, bool), cfg parallelConfig) (map[U]struct{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if cfg.workers <= 1 || len(m) == 0 || len(m) < cfg.threshold {
+		result := make(map[U]struct{})
+		i := 0
+		for k := range m {
+			if i++; i%cancelCheckInterval == 0 {
+				if err := ctx.Err(); err != nil {
+					return nil, err
+				}
+			}
+			if v, ok := fn(k); ok {
+				result[v] = struct{}{}
+			}
+		}
+		return result, nil
+	}
+
+	keys := make([]T, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	workers := cfg.workers
+	if workers > len(keys) {
+		workers = len(keys)
+	}
+	chunk := (len(keys) + workers - 1) / workers
+	// rounding the chunks up can leave the last workers with nothing to do
+	workers = (len(keys) + chunk - 1) / chunk
+	results := make([][]U, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		start, end := w*chunk, (w+1)*chunk
+		if end > len(keys) {
+			end = len(keys)
+		}
+		wg.Add(1)
+		go func(w int, part []T) {
+			defer wg.Done()
+			out := make([]U, 0, len(part))
+			for i, k := range part {
+				if i%cancelCheckInterval == 0 && ctx.Err() != nil {
+					return
+				}
+				if v, ok := fn(k); ok {
+					out = append(out, v)
+				}
+			}
+			results[w] = out
+		}(w, keys[start:end])
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	size := 0
+	for _, out := range results {
+		size += len(out)
+	}
+	result := make(map[U]struct{}, size)
+	for _, out := range results {
+		for _, v := range out {
+			result[v] = struct{}{}
+		}
+	}
+	return result, nil
+}
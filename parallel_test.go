@@ -0,0 +1,163 @@
+package set
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/drkennetz/set/settest"
+)
+
+func rangeSet(from, to int) *Set[int] {
+	s := NewSet[int]()
+	for i := from; i < to; i++ {
+		s.Add(i)
+	}
+	return s
+}
+
+func TestSet_ParallelFilter(t *testing.T) {
+	ctx := context.Background()
+	s := rangeSet(0, 50000)
+	even := func(x int) bool { return x%2 == 0 }
+	for _, opts := range [][]ParallelOption{
+		nil,
+		{WithWorkers(1)},
+		{WithWorkers(3), WithSerialThreshold(0)},
+		{WithWorkers(7), WithSerialThreshold(1)},
+		{WithSerialThreshold(1 << 20)},
+	} {
+		got, err := s.ParallelFilter(ctx, even, opts...)
+		if err != nil {
+			t.Fatalf("Set.ParallelFilter() returned %v", err)
+		}
+		settest.AssertEqual[int](t, got, s.Filter(even))
+	}
+	small := NewSetFromSlice([]int{1, 2, 3, 4, 5})
+	got, _ := small.ParallelFilter(ctx, even, WithWorkers(4), WithSerialThreshold(0))
+	settest.AssertEqual[int](t, got, NewSetFromSlice([]int{2, 4}))
+	got, _ = NewSet[int]().ParallelFilter(ctx, even, WithSerialThreshold(0))
+	if !got.IsEmpty() {
+		t.Errorf("Expected empty set, got %v", got)
+	}
+}
+
+func TestSet_ParallelMap(t *testing.T) {
+	s := rangeSet(0, 30000)
+	half := func(x int) int { return x / 2 }
+	got, err := s.ParallelMap(context.Background(), half, WithWorkers(4), WithSerialThreshold(100))
+	if err != nil {
+		t.Fatalf("Set.ParallelMap() returned %v", err)
+	}
+	settest.AssertEqual[int](t, got, s.Map(half))
+}
+
+func TestSet_ParallelUnionIntersection(t *testing.T) {
+	ctx := context.Background()
+	a, b := rangeSet(0, 20000), rangeSet(15000, 40000)
+	union, err := a.ParallelUnion(ctx, b, WithSerialThreshold(100))
+	if err != nil {
+		t.Fatalf("Set.ParallelUnion() returned %v", err)
+	}
+	settest.AssertEqual[int](t, union, a.Union(b))
+	intersection, err := b.ParallelIntersection(ctx, a, WithSerialThreshold(100))
+	if err != nil {
+		t.Fatalf("Set.ParallelIntersection() returned %v", err)
+	}
+	settest.AssertEqual[int](t, intersection, rangeSet(15000, 20000))
+}
+
+func TestSet_ParallelCancel(t *testing.T) {
+	s := rangeSet(0, 100000)
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls int64
+	_, err := s.ParallelFilter(ctx, func(int) bool {
+		if atomic.AddInt64(&calls, 1) == 10 {
+			cancel()
+		}
+		return true
+	}, WithWorkers(4), WithSerialThreshold(0))
+	if err != context.Canceled {
+		t.Errorf("Expected %v, got %v", context.Canceled, err)
+	}
+	if _, err := s.ParallelMap(ctx, func(x int) int { return x }); err != context.Canceled {
+		t.Errorf("Expected %v, got %v", context.Canceled, err)
+	}
+	if _, err := s.ParallelUnion(ctx, s); err != context.Canceled {
+		t.Errorf("Expected %v, got %v", context.Canceled, err)
+	}
+	if _, err := s.ParallelIntersection(ctx, s); err != context.Canceled {
+		t.Errorf("Expected %v, got %v", context.Canceled, err)
+	}
+	// the serial path notices cancellation too
+	ctx, cancel = context.WithCancel(context.Background())
+	calls = 0
+	_, err = s.ParallelFilter(ctx, func(int) bool {
+		if atomic.AddInt64(&calls, 1) == 10 {
+			cancel()
+		}
+		return true
+	}, WithWorkers(1))
+	if err != context.Canceled {
+		t.Errorf("Expected %v, got %v", context.Canceled, err)
+	}
+}
+
+// go test -race -run TestTSSParallel .
+func TestTSSParallel(t *testing.T) {
+	ctx := context.Background()
+	a := NewThreadSafeSetFromSlice(rangeSet(0, 20000).ToSlice())
+	b := NewThreadSafeSetFromSlice(rangeSet(10000, 30000).ToSlice())
+	opts := []ParallelOption{WithWorkers(4), WithSerialThreshold(100)}
+	odd := func(x int) bool { return x%2 == 1 }
+
+	filtered, err := a.ParallelFilter(ctx, odd, opts...)
+	if err != nil {
+		t.Fatalf("ThreadSafeSet.ParallelFilter() returned %v", err)
+	}
+	settest.AssertEqual[int](t, filtered, a.Filter(odd))
+	mapped, err := a.ParallelMap(ctx, func(x int) int { return x % 10 }, opts...)
+	if err != nil {
+		t.Fatalf("ThreadSafeSet.ParallelMap() returned %v", err)
+	}
+	settest.AssertEqual[int](t, mapped, rangeSet(0, 10))
+	union, err := a.ParallelUnion(ctx, b, opts...)
+	if err != nil {
+		t.Fatalf("ThreadSafeSet.ParallelUnion() returned %v", err)
+	}
+	settest.AssertEqual[int](t, union, rangeSet(0, 30000))
+	intersection, err := b.ParallelIntersection(ctx, a, opts...)
+	if err != nil {
+		t.Fatalf("ThreadSafeSet.ParallelIntersection() returned %v", err)
+	}
+	settest.AssertEqual[int](t, intersection, rangeSet(10000, 20000))
+
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := a.ParallelFilter(ctx, odd); err == nil {
+		t.Error("Expected an error from a cancelled context")
+	}
+	if _, err := a.ParallelMap(ctx, func(x int) int { return x }); err == nil {
+		t.Error("Expected an error from a cancelled context")
+	}
+	if _, err := a.ParallelUnion(ctx, b); err == nil {
+		t.Error("Expected an error from a cancelled context")
+	}
+	if _, err := a.ParallelIntersection(ctx, b); err == nil {
+		t.Error("Expected an error from a cancelled context")
+	}
+}
+
+func BenchmarkSet_Filter(b *testing.B) {
+	s := rangeSet(0, 1000000)
+	for i := 0; i < b.N; i++ {
+		s.Filter(func(x int) bool { return x%3 == 0 })
+	}
+}
+
+func BenchmarkSet_ParallelFilter(b *testing.B) {
+	s := rangeSet(0, 1000000)
+	for i := 0; i < b.N; i++ {
+		_, _ = s.ParallelFilter(context.Background(), func(x int) bool { return x%3 == 0 })
+	}
+}
@@ -9,7 +9,9 @@ import "fmt"
 
 // Set is a set data structure
 type Set[T comparable] struct {
-	m map[T]struct{}
+	m             map[T]struct{}
+	checkHashable bool
+	fp            fingerprintState
 }
 
 // NewSet returns a new set
@@ -29,19 +31,30 @@ func NewSetFromSlice[T comparable](s []T) *Set[T] {
 }
 
 // Add adds an element to the set
+// A set created WithHashCheck silently ignores an element whose dynamic value is unhashable
 func (s *Set[T]) Add(e T) {
-	s.m[e] = struct{}{}
+	if s.checkHashable && hashable(e) != nil {
+		return
+	}
+	s.insert(e)
 }
 
 // Contains returns true if the set contains the element
+// A set created WithHashCheck returns false for an element whose dynamic value is unhashable
 func (s *Set[T]) Contains(e T) bool {
+	if s.checkHashable && hashable(e) != nil {
+		return false
+	}
 	_, ok := s.m[e]
 	return ok
 }
 
 // Remove removes an element from the set
 func (s *Set[T]) Remove(e T) {
-	delete(s.m, e)
+	if s.checkHashable && hashable(e) != nil {
+		return
+	}
+	s.delete(e)
 }
 
 // Pop removes and returns an arbitrary element from the set or returns the zero value of T if the set is empty
@@ -54,13 +67,23 @@ func (s *Set[T]) Pop() T {
 	return zero
 }
 
+// TryPop removes and returns an arbitrary element from the set and true, or the zero value of T and false if the set is empty
+func (s *Set[T]) TryPop() (T, bool) {
+	var zero T
+	for k := range s.m {
+		s.Remove(k)
+		return k, true
+	}
+	return zero, false
+}
+
 // Intersection returns the intersection of two sets as a new set IE the values that are in both sets
 func (s *Set[T]) Intersection(s2 *Set[T]) *Set[T] {
+	s3 := s.empty()
 	// make sure s is the smaller set
 	if len(s.m) > len(s2.m) {
 		s, s2 = s2, s
 	}
-	s3 := NewSet[T]()
 	for k := range s.m {
 		if ok := s2.Contains(k); ok {
 			s3.Add(k)
@@ -71,7 +94,7 @@ func (s *Set[T]) Intersection(s2 *Set[T]) *Set[T] {
 
 // Union returns the union of two sets as a new set IE the values that are in either set without duplicates
 func (s *Set[T]) Union(s2 *Set[T]) *Set[T] {
-	s3 := NewSet[T]()
+	s3 := s.empty()
 	for k := range s.m {
 		s3.Add(k)
 	}
@@ -83,7 +106,7 @@ func (s *Set[T]) Union(s2 *Set[T]) *Set[T] {
 
 // Difference returns the values in s that are not in s2 as a new set
 func (s *Set[T]) Difference(s2 *Set[T]) *Set[T] {
-	s3 := NewSet[T]()
+	s3 := s.empty()
 	for k := range s.m {
 		if ok := s2.Contains(k); !ok {
 			s3.Add(k)
@@ -94,7 +117,7 @@ func (s *Set[T]) Difference(s2 *Set[T]) *Set[T] {
 
 // SymmetricDifference returns the values that are in one of the sets, but not both
 func (s *Set[T]) SymmetricDifference(s2 *Set[T]) *Set[T] {
-	s3 := NewSet[T]()
+	s3 := s.empty()
 	for k := range s.m {
 		if ok := s2.Contains(k); !ok {
 			s3.Add(k)
@@ -108,6 +131,68 @@ func (s *Set[T]) SymmetricDifference(s2 *Set[T]) *Set[T] {
 	return s3
 }
 
+// UnionWith adds the values in s2 to s in place
+func (s *Set[T]) UnionWith(s2 *Set[T]) {
+	for k := range s2.m {
+		s.insert(k)
+	}
+}
+
+// IntersectWith removes the values in s that are not in s2 in place
+func (s *Set[T]) IntersectWith(s2 *Set[T]) {
+	for k := range s.m {
+		if ok := s2.Contains(k); !ok {
+			s.delete(k)
+		}
+	}
+}
+
+// DifferenceWith removes the values in s2 from s in place
+func (s *Set[T]) DifferenceWith(s2 *Set[T]) {
+	// iterate over the smaller set
+	if len(s.m) < len(s2.m) {
+		for k := range s.m {
+			if ok := s2.Contains(k); ok {
+				s.delete(k)
+			}
+		}
+		return
+	}
+	for k := range s2.m {
+		s.delete(k)
+	}
+}
+
+// SymmetricDifferenceWith keeps the values that are in one of the sets, but not both, in s in place
+func (s *Set[T]) SymmetricDifferenceWith(s2 *Set[T]) {
+	for k := range s2.m {
+		if ok := s.Contains(k); ok {
+			s.delete(k)
+		} else {
+			s.insert(k)
+		}
+	}
+}
+
+// RemoveIf removes the elements that satisfy the predicate in place and returns how many were removed
+func (s *Set[T]) RemoveIf(predicate func(T) bool) int {
+	removed := 0
+	for k := range s.m {
+		if predicate(k) {
+			s.delete(k)
+			removed++
+		}
+	}
+	return removed
+}
+
+// RetainIf removes the elements that don't satisfy the predicate in place and returns how many were removed
+func (s *Set[T]) RetainIf(predicate func(T) bool) int {
+	return s.RemoveIf(func(e T) bool {
+		return !predicate(e)
+	})
+}
+
 // IsSubset returns true if s is a subset of s2 IE all values in s are in s2
 func (s *Set[T]) IsSubset(s2 *Set[T]) bool {
 	for k := range s.m {
@@ -143,7 +228,7 @@ func (s *Set[T]) IsEqual(s2 *Set[T]) bool {
 
 // Copy returns a copy of the set
 func (s *Set[T]) Copy() *Set[T] {
-	s2 := NewSet[T]()
+	s2 := s.empty()
 	for k := range s.m {
 		s2.Add(k)
 	}
@@ -158,6 +243,7 @@ func (s *Set[T]) Len() int {
 // Clear removes all elements from the set
 func (s *Set[T]) Clear() {
 	s.m = make(map[T]struct{})
+	s.fp.reset()
 }
 
 // IsEmpty returns true if the set is empty
@@ -176,7 +262,7 @@ func (s *Set[T]) ToSlice() []T {
 
 // Filter returns a new set containing only the elements that satisfy the predicate
 func (s *Set[T]) Filter(predicate func(T) bool) *Set[T] {
-	s2 := NewSet[T]()
+	s2 := s.empty()
 	for k := range s.m {
 		if predicate(k) {
 			s2.Add(k)
@@ -187,7 +273,8 @@ func (s *Set[T]) Filter(predicate func(T) bool) *Set[T] {
 
 // Map returns a new set containing the results of applying the function to each element
 func (s *Set[T]) Map(f func(T) T) *Set[T] {
-	s2 := NewSet[T]()
+	// with the hash check, s2 drops the unhashable results
+	s2 := s.empty()
 	for k := range s.m {
 		s2.Add(f(k))
 	}
@@ -203,6 +290,21 @@ func (s *Set[T]) Reduce(f func(T, T) T) T {
 	return result
 }
 
+// TryReduce applies the function to each element in the set, starting from an arbitrary element instead of the zero value of T
+// It returns the result and true, or the zero value of T and false if the set is empty
+func (s *Set[T]) TryReduce(f func(T, T) T) (T, bool) {
+	var result T
+	first := true
+	for k := range s.m {
+		if first {
+			result, first = k, false
+			continue
+		}
+		result = f(result, k)
+	}
+	return result, !first
+}
+
 // Any returns true if any element in the set satisfies the predicate
 func (s *Set[T]) Any(predicate func(T) bool) bool {
 	for k := range s.m {
@@ -227,3 +329,13 @@ func (s *Set[T]) All(predicate func(T) bool) bool {
 func (s *Set[T]) String() string {
 	return fmt.Sprintf("%v", s.ToSlice())
 }
+
+// insert adds an element, every write to the map goes through insert or delete to keep the fingerprint up to date
+func (s *Set[T]) insert(e T) {
+	insertElem(s.m, &s.fp, e)
+}
+
+// delete removes an element
+func (s *Set[T]) delete(e T) {
+	deleteElem(s.m, &s.fp, e)
+}
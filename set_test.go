@@ -3,6 +3,8 @@ package set
 import (
 	"strings"
 	"testing"
+
+	"github.com/drkennetz/set/settest"
 )
 
 func TestNewSet(t *testing.T) {
@@ -181,6 +183,21 @@ func TestSet_Pop(t *testing.T) {
 		t.Error("Set.Pop() failed to pop element")
 	}
 }
+func TestSet_TryPop(t *testing.T) {
+	a := NewSet[int]()
+	a.Add(0)
+	v, ok := a.TryPop()
+	if !ok || v != 0 {
+		t.Error("Set.TryPop() failed to pop the zero value")
+	}
+	if !a.IsEmpty() {
+		t.Error("Set.TryPop() failed to remove element")
+	}
+	if _, ok := a.TryPop(); ok {
+		t.Error("Set.TryPop() returned true for an empty set")
+	}
+}
+
 func TestSet_Intersection(t *testing.T) {
 	a := NewSet[int]()
 	a.Add(1)
@@ -289,6 +306,78 @@ func TestSet_SymmetricDifference(t *testing.T) {
 	}
 }
 
+func TestSet_UnionWith(t *testing.T) {
+	a := NewSetFromSlice([]int{1, 2, 3})
+	b := NewSetFromSlice([]int{3, 4})
+	a.UnionWith(b)
+	settest.AssertEqual[int](t, a, NewSetFromSlice([]int{1, 2, 3, 4}))
+	settest.AssertEqual[int](t, b, NewSetFromSlice([]int{3, 4}))
+	a.UnionWith(a)
+	settest.AssertEqual[int](t, a, NewSetFromSlice([]int{1, 2, 3, 4}))
+}
+
+func TestSet_IntersectWith(t *testing.T) {
+	a := NewSetFromSlice([]int{1, 2, 3})
+	a.IntersectWith(NewSetFromSlice([]int{2, 3, 4}))
+	settest.AssertEqual[int](t, a, NewSetFromSlice([]int{2, 3}))
+	a.IntersectWith(a)
+	settest.AssertEqual[int](t, a, NewSetFromSlice([]int{2, 3}))
+	a.IntersectWith(NewSet[int]())
+	if !a.IsEmpty() {
+		t.Error("Set.IntersectWith() failed to intersect with an empty set")
+	}
+}
+
+func TestSet_DifferenceWith(t *testing.T) {
+	a := NewSetFromSlice([]int{1, 2, 3, 4})
+	a.DifferenceWith(NewSetFromSlice([]int{2, 5}))
+	settest.AssertEqual[int](t, a, NewSetFromSlice([]int{1, 3, 4}))
+	// the other set is larger
+	a.DifferenceWith(NewSetFromSlice([]int{1, 5, 6, 7, 8}))
+	settest.AssertEqual[int](t, a, NewSetFromSlice([]int{3, 4}))
+	a.DifferenceWith(a)
+	if !a.IsEmpty() {
+		t.Error("Set.DifferenceWith() failed to difference a set with itself")
+	}
+}
+
+func TestSet_SymmetricDifferenceWith(t *testing.T) {
+	a := NewSetFromSlice([]int{1, 2, 3})
+	a.SymmetricDifferenceWith(NewSetFromSlice([]int{3, 4}))
+	settest.AssertEqual[int](t, a, NewSetFromSlice([]int{1, 2, 4}))
+	a.SymmetricDifferenceWith(a)
+	if !a.IsEmpty() {
+		t.Error("Set.SymmetricDifferenceWith() failed to difference a set with itself")
+	}
+}
+
+func TestSet_RemoveIf(t *testing.T) {
+	a := NewSetFromSlice([]int{1, 2, 3, 4, 5})
+	if n := a.RemoveIf(func(x int) bool { return x%2 == 0 }); n != 2 {
+		t.Errorf("Set.RemoveIf() removed %d elements, want 2", n)
+	}
+	settest.AssertEqual[int](t, a, NewSetFromSlice([]int{1, 3, 5}))
+	if n := a.RetainIf(func(x int) bool { return x > 1 }); n != 1 {
+		t.Errorf("Set.RetainIf() removed %d elements, want 1", n)
+	}
+	settest.AssertEqual[int](t, a, NewSetFromSlice([]int{3, 5}))
+}
+
+func TestSet_InPlaceAllocations(t *testing.T) {
+	a := NewSetFromSlice([]int{1, 2, 3, 4, 5})
+	b := NewSetFromSlice([]int{1, 2, 3, 4, 5})
+	odd := func(x int) bool { return x%2 == 1 }
+	allocs := testing.AllocsPerRun(100, func() {
+		a.UnionWith(b)
+		a.IntersectWith(b)
+		a.RemoveIf(odd)
+		a.DifferenceWith(b)
+	})
+	if allocs != 0 {
+		t.Errorf("In-place operations allocated %v times, want 0", allocs)
+	}
+}
+
 func TestSet_IsSubset(t *testing.T) {
 	a := NewSet[int]()
 	a.Add(1)
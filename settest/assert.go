@@ -0,0 +1,110 @@
+package settest
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// Elements is implemented by any set that can list its elements, including Set and ThreadSafeSet.
+// Before Go 1.21 the element type can't be inferred from a set argument, so callers
+// instantiate the assertions explicitly, e.g. AssertEqual[int](t, got, want).
+type Elements[T comparable] interface {
+	ToSlice() []T
+}
+
+// AssertEqual reports an error on tb if got and want do not hold the same elements.
+// The message lists the elements of want missing from got and the unexpected elements
+// of got, both sorted. It returns true if the sets are equal.
+func AssertEqual[T comparable](tb testing.TB, got, want Elements[T]) bool {
+	tb.Helper()
+	missing, unexpected := diff(got.ToSlice(), want.ToSlice())
+	if len(missing) == 0 && len(unexpected) == 0 {
+		return true
+	}
+	tb.Errorf("sets are not equal: missing: %s, unexpected: %s", format(missing), format(unexpected))
+	return false
+}
+
+// AssertSubset reports an error on tb if sub is not a subset of super.
+// The message lists the sorted elements of sub that are missing from super.
+// It returns true if sub is a subset of super.
+func AssertSubset[T comparable](tb testing.TB, sub, super Elements[T]) bool {
+	tb.Helper()
+	_, missing := diff(sub.ToSlice(), super.ToSlice())
+	if len(missing) == 0 {
+		return true
+	}
+	tb.Errorf("set is not a subset: missing: %s", format(missing))
+	return false
+}
+
+// AssertContains reports an error on tb if s does not contain all of elems.
+// The message lists the sorted elements that are missing from s.
+// It returns true if every element is in s.
+func AssertContains[T comparable](tb testing.TB, s Elements[T], elems ...T) bool {
+	tb.Helper()
+	missing, _ := diff(s.ToSlice(), elems)
+	if len(missing) == 0 {
+		return true
+	}
+	tb.Errorf("set does not contain all elements: missing: %s", format(missing))
+	return false
+}
+
+// diff returns the elements of want that are not in got, and the elements of got that are not in want
+func diff[T comparable](got, want []T) (missing, unexpected []T) {
+	inGot := make(map[T]struct{}, len(got))
+	for _, e := range got {
+		inGot[e] = struct{}{}
+	}
+	inWant := make(map[T]struct{}, len(want))
+	for _, e := range want {
+		if _, ok := inWant[e]; ok {
+			continue
+		}
+		inWant[e] = struct{}{}
+		if _, ok := inGot[e]; !ok {
+			missing = append(missing, e)
+		}
+	}
+	for e := range inGot {
+		if _, ok := inWant[e]; !ok {
+			unexpected = append(unexpected, e)
+		}
+	}
+	return missing, unexpected
+}
+
+// format returns the elements sorted and formatted like {1, 2, 3}
+func format[T comparable](elems []T) string {
+	sorted := append(make([]T, 0, len(elems)), elems...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return less(sorted[i], sorted[j])
+	})
+	parts := make([]string, len(sorted))
+	for i, e := range sorted {
+		parts[i] = fmt.Sprint(e)
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// less orders numbers and strings naturally and everything else by its formatted value
+func less[T comparable](a, b T) bool {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if va.IsValid() && vb.IsValid() && va.Kind() == vb.Kind() {
+		switch va.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return va.Int() < vb.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return va.Uint() < vb.Uint()
+		case reflect.Float32, reflect.Float64:
+			return va.Float() < vb.Float()
+		case reflect.String:
+			return va.String() < vb.String()
+		}
+	}
+	return fmt.Sprint(a) < fmt.Sprint(b)
+}
@@ -0,0 +1,12 @@
+//go:build go1.20
+
+package settest
+
+import "testing"
+
+// interface types only satisfy comparable from Go 1.20
+func TestFormat_Interface(t *testing.T) {
+	if got, want := format([]any{"b", 1, "a"}), "{1, a, b}"; got != want {
+		t.Errorf("format() = %q, want %q", got, want)
+	}
+}
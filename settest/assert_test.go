@@ -0,0 +1,86 @@
+package settest
+
+import (
+	"fmt"
+	"testing"
+)
+
+// recorder captures the errors reported by the assertions
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+// elems is a minimal Elements implementation
+type elems[T comparable] []T
+
+func (e elems[T]) ToSlice() []T {
+	return e
+}
+
+func TestAssertEqual(t *testing.T) {
+	r := &recorder{}
+	if !AssertEqual[int](r, elems[int]{3, 1, 2}, elems[int]{1, 2, 3}) {
+		t.Error("AssertEqual() = false for equal sets")
+	}
+	if len(r.errors) != 0 {
+		t.Errorf("AssertEqual() reported %v for equal sets", r.errors)
+	}
+	if AssertEqual[int](r, elems[int]{10, 2, 7, 4}, elems[int]{4, 3, 1, 10}) {
+		t.Error("AssertEqual() = true for different sets")
+	}
+	want := "sets are not equal: missing: {1, 3}, unexpected: {2, 7}"
+	if len(r.errors) != 1 || r.errors[0] != want {
+		t.Errorf("AssertEqual() reported %q, want %q", r.errors, want)
+	}
+}
+
+func TestAssertSubset(t *testing.T) {
+	r := &recorder{}
+	if !AssertSubset[string](r, elems[string]{"a"}, elems[string]{"a", "b"}) {
+		t.Error("AssertSubset() = false for a subset")
+	}
+	if AssertSubset[string](r, elems[string]{"c", "a", "b"}, elems[string]{"b"}) {
+		t.Error("AssertSubset() = true for a set that is not a subset")
+	}
+	want := "set is not a subset: missing: {a, c}"
+	if len(r.errors) != 1 || r.errors[0] != want {
+		t.Errorf("AssertSubset() reported %q, want %q", r.errors, want)
+	}
+}
+
+func TestAssertContains(t *testing.T) {
+	r := &recorder{}
+	if !AssertContains[float64](r, elems[float64]{1.5, 2}, 2) {
+		t.Error("AssertContains() = false for a contained element")
+	}
+	if AssertContains[float64](r, elems[float64]{1.5}, 1.5, 10, 2.5) {
+		t.Error("AssertContains() = true for missing elements")
+	}
+	want := "set does not contain all elements: missing: {2.5, 10}"
+	if len(r.errors) != 1 || r.errors[0] != want {
+		t.Errorf("AssertContains() reported %q, want %q", r.errors, want)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	type point struct{ x, y int }
+	tests := []struct {
+		got, want string
+	}{
+		{format([]int{}), "{}"},
+		{format([]uint8{20, 3}), "{3, 20}"},
+		{format([]point{{2, 1}, {1, 2}}), "{{1 2}, {2 1}}"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("format() = %q, want %q", tt.got, tt.want)
+		}
+	}
+}
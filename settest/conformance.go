@@ -0,0 +1,589 @@
+// Package settest provides helpers for testing set implementations.
+// RunConformance and FuzzConformance check that a type behaves like the
+// Set type in github.com/drkennetz/set, so wrappers around it (metrics,
+// persistence, etc.) can prove they keep its semantics.
+package settest
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// Interface is the method set shared by the set types in github.com/drkennetz/set.
+// S is the concrete set type, which the binary operations accept and return.
+type Interface[T comparable, S any] interface {
+	Add(e T)
+	Contains(e T) bool
+	Remove(e T)
+	Pop() T
+	TryPop() (T, bool)
+	Intersection(s2 S) S
+	Union(s2 S) S
+	Difference(s2 S) S
+	SymmetricDifference(s2 S) S
+	UnionWith(s2 S)
+	IntersectWith(s2 S)
+	DifferenceWith(s2 S)
+	SymmetricDifferenceWith(s2 S)
+	RemoveIf(predicate func(T) bool) int
+	RetainIf(predicate func(T) bool) int
+	IsSubset(s2 S) bool
+	IsSuperset(s2 S) bool
+	IsDisjoint(s2 S) bool
+	IsEqual(s2 S) bool
+	Copy() S
+	Len() int
+	Clear()
+	IsEmpty() bool
+	ToSlice() []T
+	Filter(predicate func(T) bool) S
+	Map(f func(T) T) S
+	Reduce(f func(T, T) T) T
+	TryReduce(f func(T, T) T) (T, bool)
+	Any(predicate func(T) bool) bool
+	All(predicate func(T) bool) bool
+	String() string
+}
+
+// universe is the set every conformance case draws its elements from.
+// It is also the complement used when checking De Morgan's laws.
+var universe = []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}
+
+// RunConformance runs the conformance suite against the sets returned by factory.
+// factory must return a new, empty set on every call.
+func RunConformance[S Interface[int, S]](t *testing.T, factory func() S) {
+	t.Helper()
+	c := conformance[S]{factory: factory}
+	t.Run("Empty", c.testEmpty)
+	t.Run("AddContainsRemove", c.testAddContainsRemove)
+	t.Run("Pop", c.testPop)
+	t.Run("BinaryOperations", c.testBinaryOperations)
+	t.Run("Predicates", c.testPredicates)
+	t.Run("InPlace", c.testInPlace)
+	t.Run("OperandsUnchanged", c.testOperandsUnchanged)
+	t.Run("Commutativity", c.testCommutativity)
+	t.Run("Associativity", c.testAssociativity)
+	t.Run("Distributivity", c.testDistributivity)
+	t.Run("DeMorgan", c.testDeMorgan)
+	t.Run("Identity", c.testIdentity)
+	t.Run("CopyClear", c.testCopyClear)
+	t.Run("Functional", c.testFunctional)
+	t.Run("String", c.testString)
+}
+
+// FuzzConformance registers a fuzz target that applies a random sequence of
+// mutations to two sets built by factory and checks every operation against
+// a map based model after each step.
+func FuzzConformance[S Interface[int, S]](f *testing.F, factory func() S) {
+	f.Helper()
+	f.Add([]byte{})
+	f.Add([]byte{0, 1, 0, 2, 1, 2, 1, 3})
+	f.Add([]byte{0, 1, 2, 1, 4, 0, 5, 0, 0, 7})
+	f.Add([]byte{0, 3, 1, 3, 3, 0, 3, 0, 4, 0})
+	c := conformance[S]{factory: factory}
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		a, b := factory(), factory()
+		ma, mb := map[int]bool{}, map[int]bool{}
+		for i := 0; i+1 < len(ops); i += 2 {
+			e := universe[int(ops[i+1])%len(universe)]
+			switch ops[i] % 6 {
+			case 0:
+				a.Add(e)
+				ma[e] = true
+			case 1:
+				b.Add(e)
+				mb[e] = true
+			case 2:
+				a.Remove(e)
+				delete(ma, e)
+			case 3:
+				b.Remove(e)
+				delete(mb, e)
+			case 4:
+				if a.IsEmpty() {
+					if p := a.Pop(); p != 0 {
+						t.Fatalf("Pop() on empty set returned %d, want 0", p)
+					}
+					if _, ok := a.TryPop(); ok {
+						t.Fatal("TryPop() on empty set returned true")
+					}
+					continue
+				}
+				p, ok := a.TryPop()
+				if !ok || !ma[p] {
+					t.Fatalf("TryPop() returned %d, %v which was not in the set", p, ok)
+				}
+				delete(ma, p)
+			case 5:
+				a.Clear()
+				ma = map[int]bool{}
+			}
+			c.checkAgainstModel(t, a, b, ma, mb)
+		}
+	})
+}
+
+type conformance[S Interface[int, S]] struct {
+	factory func() S
+}
+
+// build returns a new set holding elems
+func (c conformance[S]) build(elems ...int) S {
+	s := c.factory()
+	for _, e := range elems {
+		s.Add(e)
+	}
+	return s
+}
+
+// cases returns the pairs of element lists binary operations are checked against
+func (c conformance[S]) cases() [][2][]int {
+	cases := [][2][]int{
+		{{}, {}},
+		{{1}, {}},
+		{{}, {1}},
+		{{1, 2, 3}, {3, 4, 5}},
+		{{1, 2}, {1, 2}},
+		{{1, 2, 3, 4}, {2, 3}},
+		{{0}, {0, 11}},
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		cases = append(cases, [2][]int{randomElems(r), randomElems(r)})
+	}
+	return cases
+}
+
+// triples returns random groups of three element lists for the three operand laws
+func (c conformance[S]) triples() [][3][]int {
+	r := rand.New(rand.NewSource(2))
+	triples := [][3][]int{{{}, {}, {}}, {{1}, {1}, {1}}}
+	for i := 0; i < 20; i++ {
+		triples = append(triples, [3][]int{randomElems(r), randomElems(r), randomElems(r)})
+	}
+	return triples
+}
+
+func randomElems(r *rand.Rand) []int {
+	var elems []int
+	for _, e := range universe {
+		if r.Intn(2) == 0 {
+			elems = append(elems, e)
+		}
+	}
+	return elems
+}
+
+func (c conformance[S]) testEmpty(t *testing.T) {
+	s := c.factory()
+	if s.Len() != 0 {
+		t.Errorf("Len() of a new set = %d, want 0", s.Len())
+	}
+	if !s.IsEmpty() {
+		t.Error("IsEmpty() of a new set = false, want true")
+	}
+	if s.Contains(0) {
+		t.Error("Contains(0) of a new set = true, want false")
+	}
+	if got := s.Pop(); got != 0 {
+		t.Errorf("Pop() of an empty set = %d, want the zero value", got)
+	}
+	if !s.IsEmpty() {
+		t.Error("Pop() of an empty set left it non-empty")
+	}
+	if got := s.ToSlice(); len(got) != 0 {
+		t.Errorf("ToSlice() of an empty set = %v, want []", got)
+	}
+	if got := s.Reduce(func(a, b int) int { return a + b + 1 }); got != 0 {
+		t.Errorf("Reduce() of an empty set = %d, want the zero value", got)
+	}
+	if s.Any(func(int) bool { return true }) {
+		t.Error("Any() of an empty set = true, want false")
+	}
+	if !s.All(func(int) bool { return false }) {
+		t.Error("All() of an empty set = false, want true")
+	}
+	empty := c.factory()
+	if !s.IsSubset(empty) || !s.IsSuperset(empty) || !s.IsEqual(empty) || !s.IsDisjoint(empty) {
+		t.Error("an empty set must be a subset, superset, equal to and disjoint from another empty set")
+	}
+}
+
+func (c conformance[S]) testAddContainsRemove(t *testing.T) {
+	s := c.factory()
+	s.Add(1)
+	s.Add(1)
+	s.Add(2)
+	if s.Len() != 2 {
+		t.Errorf("Len() after adding 1, 1, 2 = %d, want 2", s.Len())
+	}
+	if !s.Contains(1) || !s.Contains(2) {
+		t.Error("Contains() = false for an added element")
+	}
+	if s.Contains(3) {
+		t.Error("Contains(3) = true for an element that was never added")
+	}
+	s.Remove(3)
+	if s.Len() != 2 {
+		t.Errorf("Remove() of a missing element changed Len() to %d", s.Len())
+	}
+	s.Remove(1)
+	if s.Contains(1) {
+		t.Error("Contains(1) = true after Remove(1)")
+	}
+	if s.Len() != 1 {
+		t.Errorf("Len() after Remove(1) = %d, want 1", s.Len())
+	}
+	s.Add(0)
+	if !s.Contains(0) {
+		t.Error("Contains(0) = false after Add(0); the zero value must be storable")
+	}
+}
+
+func (c conformance[S]) testPop(t *testing.T) {
+	s := c.build(1, 2, 3)
+	seen := map[int]bool{}
+	for i := 0; i < 3; i++ {
+		e := s.Pop()
+		if seen[e] || e < 1 || e > 3 {
+			t.Fatalf("Pop() returned %d, want an unpopped element of {1, 2, 3}", e)
+		}
+		seen[e] = true
+		if s.Contains(e) {
+			t.Errorf("Pop() returned %d but left it in the set", e)
+		}
+	}
+	if !s.IsEmpty() {
+		t.Errorf("set has %d elements after popping all of them", s.Len())
+	}
+	if got := s.Pop(); got != 0 {
+		t.Errorf("Pop() of an emptied set = %d, want the zero value", got)
+	}
+	// unlike Pop, TryPop tells the zero value apart from an empty set
+	s = c.build(0, 1)
+	seen = map[int]bool{}
+	for i := 0; i < 2; i++ {
+		e, ok := s.TryPop()
+		if !ok || seen[e] || (e != 0 && e != 1) {
+			t.Fatalf("TryPop() = %d, %v, want an unpopped element of {0, 1} and true", e, ok)
+		}
+		seen[e] = true
+		if s.Contains(e) {
+			t.Errorf("TryPop() returned %d but left it in the set", e)
+		}
+	}
+	if e, ok := s.TryPop(); ok || e != 0 {
+		t.Errorf("TryPop() of an emptied set = %d, %v, want 0, false", e, ok)
+	}
+}
+
+func (c conformance[S]) testBinaryOperations(t *testing.T) {
+	for _, tc := range c.cases() {
+		a, b := c.build(tc[0]...), c.build(tc[1]...)
+		ma, mb := toModel(tc[0]), toModel(tc[1])
+		c.checkAgainstModel(t, a, b, ma, mb)
+	}
+}
+
+func (c conformance[S]) testPredicates(t *testing.T) {
+	a, b := c.build(1, 2), c.build(1, 2, 3)
+	if !a.IsSubset(b) || b.IsSubset(a) {
+		t.Error("IsSubset() wrong for {1, 2} and {1, 2, 3}")
+	}
+	if !b.IsSuperset(a) || a.IsSuperset(b) {
+		t.Error("IsSuperset() wrong for {1, 2, 3} and {1, 2}")
+	}
+	if a.IsDisjoint(b) || !a.IsDisjoint(c.build(4)) {
+		t.Error("IsDisjoint() wrong for {1, 2}")
+	}
+	if a.IsEqual(b) || !a.IsEqual(c.build(2, 1)) {
+		t.Error("IsEqual() wrong for {1, 2}")
+	}
+	if !a.IsSubset(a.Copy()) || !a.IsSuperset(a.Copy()) {
+		t.Error("a set must be a subset and superset of itself")
+	}
+}
+
+func (c conformance[S]) testInPlace(t *testing.T) {
+	even := func(e int) bool { return e%2 == 0 }
+	for _, tc := range c.cases() {
+		var evens, odds []int
+		for _, e := range fromModel(toModel(tc[0])) {
+			if even(e) {
+				evens = append(evens, e)
+			} else {
+				odds = append(odds, e)
+			}
+		}
+		a := c.build(tc[0]...)
+		if n := a.RemoveIf(even); n != len(evens) {
+			t.Errorf("RemoveIf(even) = %d, want %d for A=%v", n, len(evens), tc[0])
+		}
+		c.expect(t, "RemoveIf(even)", a, odds)
+		a = c.build(tc[0]...)
+		if n := a.RetainIf(even); n != len(odds) {
+			t.Errorf("RetainIf(even) = %d, want %d for A=%v", n, len(odds), tc[0])
+		}
+		c.expect(t, "RetainIf(even)", a, evens)
+		// a set passed as both operands
+		a = c.build(tc[0]...)
+		a.UnionWith(a)
+		a.IntersectWith(a)
+		c.expect(t, "A ∪= A, A ∩= A", a, tc[0])
+		a.DifferenceWith(a)
+		c.expect(t, "A \\= A", a, nil)
+		a = c.build(tc[0]...)
+		a.SymmetricDifferenceWith(a)
+		c.expect(t, "A △= A", a, nil)
+	}
+}
+
+func (c conformance[S]) testOperandsUnchanged(t *testing.T) {
+	a, b := c.build(1, 2, 3), c.build(3, 4)
+	a.Union(b)
+	a.Intersection(b)
+	a.Difference(b)
+	a.SymmetricDifference(b)
+	a.IsSubset(b)
+	a.IsSuperset(b)
+	a.IsDisjoint(b)
+	a.IsEqual(b)
+	a.Copy()
+	a.Filter(func(int) bool { return false })
+	a.Map(func(int) int { return 0 })
+	c.expect(t, "left operand", a, []int{1, 2, 3})
+	c.expect(t, "right operand", b, []int{3, 4})
+}
+
+func (c conformance[S]) testCommutativity(t *testing.T) {
+	for _, tc := range c.cases() {
+		a, b := c.build(tc[0]...), c.build(tc[1]...)
+		if !a.Union(b).IsEqual(b.Union(a)) {
+			t.Errorf("A ∪ B != B ∪ A for A=%v B=%v", tc[0], tc[1])
+		}
+		if !a.Intersection(b).IsEqual(b.Intersection(a)) {
+			t.Errorf("A ∩ B != B ∩ A for A=%v B=%v", tc[0], tc[1])
+		}
+		if !a.SymmetricDifference(b).IsEqual(b.SymmetricDifference(a)) {
+			t.Errorf("A △ B != B △ A for A=%v B=%v", tc[0], tc[1])
+		}
+		if a.IsDisjoint(b) != b.IsDisjoint(a) {
+			t.Errorf("IsDisjoint is not symmetric for A=%v B=%v", tc[0], tc[1])
+		}
+	}
+}
+
+func (c conformance[S]) testAssociativity(t *testing.T) {
+	for _, tc := range c.triples() {
+		a, b, d := c.build(tc[0]...), c.build(tc[1]...), c.build(tc[2]...)
+		if !a.Union(b).Union(d).IsEqual(a.Union(b.Union(d))) {
+			t.Errorf("(A ∪ B) ∪ C != A ∪ (B ∪ C) for %v", tc)
+		}
+		if !a.Intersection(b).Intersection(d).IsEqual(a.Intersection(b.Intersection(d))) {
+			t.Errorf("(A ∩ B) ∩ C != A ∩ (B ∩ C) for %v", tc)
+		}
+		if !a.SymmetricDifference(b).SymmetricDifference(d).IsEqual(a.SymmetricDifference(b.SymmetricDifference(d))) {
+			t.Errorf("(A △ B) △ C != A △ (B △ C) for %v", tc)
+		}
+	}
+}
+
+func (c conformance[S]) testDistributivity(t *testing.T) {
+	for _, tc := range c.triples() {
+		a, b, d := c.build(tc[0]...), c.build(tc[1]...), c.build(tc[2]...)
+		if !a.Intersection(b.Union(d)).IsEqual(a.Intersection(b).Union(a.Intersection(d))) {
+			t.Errorf("A ∩ (B ∪ C) != (A ∩ B) ∪ (A ∩ C) for %v", tc)
+		}
+		if !a.Union(b.Intersection(d)).IsEqual(a.Union(b).Intersection(a.Union(d))) {
+			t.Errorf("A ∪ (B ∩ C) != (A ∪ B) ∩ (A ∪ C) for %v", tc)
+		}
+	}
+}
+
+func (c conformance[S]) testDeMorgan(t *testing.T) {
+	u := c.build(universe...)
+	for _, tc := range c.cases() {
+		a, b := c.build(tc[0]...), c.build(tc[1]...)
+		if !u.Difference(a.Union(b)).IsEqual(u.Difference(a).Intersection(u.Difference(b))) {
+			t.Errorf("U \\ (A ∪ B) != (U \\ A) ∩ (U \\ B) for A=%v B=%v", tc[0], tc[1])
+		}
+		if !u.Difference(a.Intersection(b)).IsEqual(u.Difference(a).Union(u.Difference(b))) {
+			t.Errorf("U \\ (A ∩ B) != (U \\ A) ∪ (U \\ B) for A=%v B=%v", tc[0], tc[1])
+		}
+		if !a.SymmetricDifference(b).IsEqual(a.Difference(b).Union(b.Difference(a))) {
+			t.Errorf("A △ B != (A \\ B) ∪ (B \\ A) for A=%v B=%v", tc[0], tc[1])
+		}
+	}
+}
+
+func (c conformance[S]) testIdentity(t *testing.T) {
+	for _, tc := range c.cases() {
+		a, empty := c.build(tc[0]...), c.factory()
+		c.expect(t, "A ∪ ∅", a.Union(empty), tc[0])
+		c.expect(t, "A ∩ ∅", a.Intersection(empty), nil)
+		c.expect(t, "A \\ ∅", a.Difference(empty), tc[0])
+		c.expect(t, "∅ \\ A", empty.Difference(a), nil)
+		c.expect(t, "A △ ∅", a.SymmetricDifference(empty), tc[0])
+		c.expect(t, "A ∪ A", a.Union(a), tc[0])
+		c.expect(t, "A ∩ A", a.Intersection(a), tc[0])
+		c.expect(t, "A \\ A", a.Difference(a), nil)
+		c.expect(t, "A △ A", a.SymmetricDifference(a), nil)
+		if !a.IsEqual(a) || !a.IsSubset(a) || !a.IsSuperset(a) || a.IsDisjoint(a) != a.IsEmpty() {
+			t.Errorf("a set passed as both operands compares wrong for A=%v", tc[0])
+		}
+	}
+}
+
+func (c conformance[S]) testCopyClear(t *testing.T) {
+	a := c.build(1, 2, 3)
+	b := a.Copy()
+	c.expect(t, "Copy()", b, []int{1, 2, 3})
+	b.Add(4)
+	a.Remove(1)
+	c.expect(t, "original after mutating the copy", a, []int{2, 3})
+	c.expect(t, "copy after mutating the original", b, []int{1, 2, 3, 4})
+	a.Clear()
+	if !a.IsEmpty() || a.Len() != 0 {
+		t.Errorf("Clear() left %d elements", a.Len())
+	}
+	a.Add(5)
+	c.expect(t, "Add() after Clear()", a, []int{5})
+	c.expect(t, "copy after clearing the original", b, []int{1, 2, 3, 4})
+}
+
+func (c conformance[S]) testFunctional(t *testing.T) {
+	a := c.build(1, 2, 3, 4)
+	c.expect(t, "Filter(even)", a.Filter(func(e int) bool { return e%2 == 0 }), []int{2, 4})
+	c.expect(t, "Filter(none)", a.Filter(func(int) bool { return false }), nil)
+	c.expect(t, "Map(double)", a.Map(func(e int) int { return e * 2 }), []int{2, 4, 6, 8})
+	c.expect(t, "Map(constant)", a.Map(func(int) int { return 7 }), []int{7})
+	if got := a.Reduce(func(acc, e int) int { return acc + e }); got != 10 {
+		t.Errorf("Reduce(sum) = %d, want 10", got)
+	}
+	if got := c.build(5).Reduce(func(acc, e int) int { return acc*10 + e }); got != 5 {
+		t.Errorf("Reduce() of {5} = %d, want 5 (the accumulator starts at the zero value)", got)
+	}
+	// TryReduce starts from an element, so the zero value doesn't take part
+	if got, ok := c.build(-3, -1, -2).TryReduce(func(acc, e int) int {
+		if e > acc {
+			return e
+		}
+		return acc
+	}); !ok || got != -1 {
+		t.Errorf("TryReduce(max) of {-3, -1, -2} = %d, %v, want -1, true", got, ok)
+	}
+	if got, ok := c.build(5).TryReduce(func(acc, e int) int { return acc*10 + e }); !ok || got != 5 {
+		t.Errorf("TryReduce() of {5} = %d, %v, want 5, true", got, ok)
+	}
+	if got, ok := c.factory().TryReduce(func(acc, e int) int { return acc + e + 1 }); ok || got != 0 {
+		t.Errorf("TryReduce() of an empty set = %d, %v, want 0, false", got, ok)
+	}
+	if !a.Any(func(e int) bool { return e == 3 }) || a.Any(func(e int) bool { return e == 5 }) {
+		t.Error("Any() wrong for {1, 2, 3, 4}")
+	}
+	if !a.All(func(e int) bool { return e > 0 }) || a.All(func(e int) bool { return e > 1 }) {
+		t.Error("All() wrong for {1, 2, 3, 4}")
+	}
+}
+
+func (c conformance[S]) testString(t *testing.T) {
+	if got := c.factory().String(); got != "[]" {
+		t.Errorf("String() of an empty set = %q, want %q", got, "[]")
+	}
+	if got := c.build(7).String(); got != "[7]" {
+		t.Errorf("String() of {7} = %q, want %q", got, "[7]")
+	}
+}
+
+// checkAgainstModel compares the results of every binary operation on a and b with the models ma and mb
+func (c conformance[S]) checkAgainstModel(t *testing.T, a, b S, ma, mb map[int]bool) {
+	t.Helper()
+	var union, inter, diff, symDiff []int
+	subset, disjoint := true, true
+	for _, e := range universe {
+		switch {
+		case ma[e] && mb[e]:
+			union = append(union, e)
+			inter = append(inter, e)
+			disjoint = false
+		case ma[e]:
+			union = append(union, e)
+			diff = append(diff, e)
+			symDiff = append(symDiff, e)
+			subset = false
+		case mb[e]:
+			union = append(union, e)
+			symDiff = append(symDiff, e)
+		}
+	}
+	superset := len(inter) == len(mb)
+	c.expect(t, "A", a, fromModel(ma))
+	c.expect(t, "B", b, fromModel(mb))
+	c.expect(t, "A ∪ B", a.Union(b), union)
+	c.expect(t, "A ∩ B", a.Intersection(b), inter)
+	c.expect(t, "A \\ B", a.Difference(b), diff)
+	c.expect(t, "A △ B", a.SymmetricDifference(b), symDiff)
+	// the in-place operations work on copies, so a and b are unchanged
+	for _, op := range []struct {
+		name string
+		fn   func(S, S)
+		want []int
+	}{
+		{"A ∪= B", S.UnionWith, union},
+		{"A ∩= B", S.IntersectWith, inter},
+		{"A \\= B", S.DifferenceWith, diff},
+		{"A △= B", S.SymmetricDifferenceWith, symDiff},
+	} {
+		x := a.Copy()
+		op.fn(x, b)
+		c.expect(t, op.name, x, op.want)
+	}
+	c.expect(t, "B after the in-place operations", b, fromModel(mb))
+	if got := a.IsSubset(b); got != subset {
+		t.Errorf("IsSubset() = %v, want %v for A=%v B=%v", got, subset, fromModel(ma), fromModel(mb))
+	}
+	if got := a.IsSuperset(b); got != superset {
+		t.Errorf("IsSuperset() = %v, want %v for A=%v B=%v", got, superset, fromModel(ma), fromModel(mb))
+	}
+	if got := a.IsDisjoint(b); got != disjoint {
+		t.Errorf("IsDisjoint() = %v, want %v for A=%v B=%v", got, disjoint, fromModel(ma), fromModel(mb))
+	}
+	if got, want := a.IsEqual(b), subset && superset; got != want {
+		t.Errorf("IsEqual() = %v, want %v for A=%v B=%v", got, want, fromModel(ma), fromModel(mb))
+	}
+}
+
+// expect reports an error if s does not hold exactly the elements in want
+func (c conformance[S]) expect(t *testing.T, name string, s S, want []int) {
+	t.Helper()
+	missing, unexpected := diff(s.ToSlice(), want)
+	if len(missing) != 0 || len(unexpected) != 0 {
+		t.Errorf("%s: missing: %s, unexpected: %s", name, format(missing), format(unexpected))
+	}
+	if s.Len() != len(toModel(want)) {
+		t.Errorf("%s: Len() = %d, want %d", name, s.Len(), len(toModel(want)))
+	}
+	for _, e := range want {
+		if !s.Contains(e) {
+			t.Errorf("%s: Contains(%d) = false, want true", name, e)
+		}
+	}
+}
+
+func toModel(elems []int) map[int]bool {
+	m := make(map[int]bool, len(elems))
+	for _, e := range elems {
+		m[e] = true
+	}
+	return m
+}
+
+func fromModel(m map[int]bool) []int {
+	elems := make([]int, 0, len(m))
+	for e := range m {
+		elems = append(elems, e)
+	}
+	sort.Ints(elems)
+	return elems
+}
@@ -0,0 +1,217 @@
+package set
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normalizer maps a string to the form a StringSet compares it by
+type Normalizer func(string) string
+
+// FoldCase is a Normalizer that maps every letter to a canonical case using Unicode simple case folding,
+// the same folding as strings.EqualFold, so "K", "k" and the Kelvin sign are equal but "ß" and "SS" differ
+func FoldCase(s string) string {
+	return strings.Map(func(r rune) rune {
+		// the canonical form is the smallest rune of the fold orbit
+		min := r
+		for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+			if f < min {
+				min = f
+			}
+		}
+		// prefer the lower case form when it is in the orbit, as it is easier to read
+		if l := unicode.ToLower(min); strings.EqualFold(string(l), string(min)) {
+			return l
+		}
+		return min
+	}, s)
+}
+
+// NFC is a Normalizer that puts a string in Unicode normalization form C, so precomposed
+// and decomposed spellings of the same characters, like "é" and "e\u0301", are equal
+func NFC(s string) string {
+	return norm.NFC.String(s)
+}
+
+// TrimSpace is a Normalizer that removes leading and trailing white space
+func TrimSpace(s string) string {
+	return strings.TrimSpace(s)
+}
+
+// StringSet is a set of strings compared by their normalized form.
+// The normalizers run in order on every string the methods take, and the set
+// remembers the first spelling it saw of each normalized form, which ToSlice returns.
+type StringSet struct {
+	s           *Set[string]
+	original    map[string]string
+	normalizers []Normalizer
+}
+
+// NewStringSet returns a new string set
+func NewStringSet(normalizers ...Normalizer) *StringSet {
+	return &StringSet{
+		s:           NewSet[string](),
+		original:    make(map[string]string),
+		normalizers: normalizers,
+	}
+}
+
+// NewStringSetFromSlice returns a new string set from a slice, keeping the first spelling of each normalized form
+func NewStringSetFromSlice(s []string, normalizers ...Normalizer) *StringSet {
+	set := NewStringSet(normalizers...)
+	for _, v := range s {
+		set.Add(v)
+	}
+	return set
+}
+
+// Normalize returns the normalized form of the string
+func (s *StringSet) Normalize(e string) string {
+	for _, n := range s.normalizers {
+		e = n(e)
+	}
+	return e
+}
+
+// Add adds a string to the set and returns true if its normalized form was not in the set
+// An existing spelling is kept
+func (s *StringSet) Add(e string) bool {
+	return s.add(s.Normalize(e), e)
+}
+
+// Contains returns true if the set contains the normalized form of the string
+func (s *StringSet) Contains(e string) bool {
+	return s.s.Contains(s.Normalize(e))
+}
+
+// Original returns the spelling stored for the normalized form of the string and true, or "" and false if it is not in the set
+func (s *StringSet) Original(e string) (string, bool) {
+	original, ok := s.original[s.Normalize(e)]
+	return original, ok
+}
+
+// Remove removes the normalized form of the string from the set
+func (s *StringSet) Remove(e string) {
+	n := s.Normalize(e)
+	s.s.Remove(n)
+	delete(s.original, n)
+}
+
+// Union returns the strings in either set as a new set with the normalizers of s
+// The strings of s2 are normalized again, and the spellings in s are kept
+func (s *StringSet) Union(s2 *StringSet) *StringSet {
+	s3 := s.Copy()
+	for _, e := range s2.original {
+		s3.Add(e)
+	}
+	return s3
+}
+
+// Intersection returns the strings in both sets as a new set with the normalizers and spellings of s
+func (s *StringSet) Intersection(s2 *StringSet) *StringSet {
+	return s.Filter(s2.Contains)
+}
+
+// Difference returns the strings in s that are not in s2 as a new set with the normalizers and spellings of s
+func (s *StringSet) Difference(s2 *StringSet) *StringSet {
+	return s.Filter(func(e string) bool {
+		return !s2.Contains(e)
+	})
+}
+
+// SymmetricDifference returns the strings that are in one of the sets, but not both, as a new set with the normalizers of s
+func (s *StringSet) SymmetricDifference(s2 *StringSet) *StringSet {
+	s3 := s.Difference(s2)
+	for _, e := range s2.original {
+		if !s.Contains(e) {
+			s3.Add(e)
+		}
+	}
+	return s3
+}
+
+// IsSubset returns true if every string in s is in s2
+func (s *StringSet) IsSubset(s2 *StringSet) bool {
+	for _, e := range s.original {
+		if !s2.Contains(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuperset returns true if every string in s2 is in s
+func (s *StringSet) IsSuperset(s2 *StringSet) bool {
+	return s2.IsSubset(s)
+}
+
+// IsEqual returns true if s and s2 contain the same strings after normalization
+func (s *StringSet) IsEqual(s2 *StringSet) bool {
+	return s.IsSubset(s2) && s.IsSuperset(s2)
+}
+
+// Filter returns a new set containing only the strings whose stored spelling satisfies the predicate
+func (s *StringSet) Filter(predicate func(string) bool) *StringSet {
+	s2 := NewStringSet(s.normalizers...)
+	for n, e := range s.original {
+		if predicate(e) {
+			s2.add(n, e)
+		}
+	}
+	return s2
+}
+
+// Copy returns a copy of the set
+func (s *StringSet) Copy() *StringSet {
+	return s.Filter(func(string) bool {
+		return true
+	})
+}
+
+// Normalized returns the normalized forms of the strings as a new set
+func (s *StringSet) Normalized() *Set[string] {
+	return s.s.Copy()
+}
+
+// Len returns the number of strings in the set
+func (s *StringSet) Len() int {
+	return s.s.Len()
+}
+
+// Clear removes all strings from the set
+func (s *StringSet) Clear() {
+	s.s.Clear()
+	s.original = make(map[string]string)
+}
+
+// IsEmpty returns true if the set is empty
+func (s *StringSet) IsEmpty() bool {
+	return s.Len() == 0
+}
+
+// ToSlice returns a slice of the stored spellings of the strings in the set
+func (s *StringSet) ToSlice() []string {
+	slice := make([]string, 0, len(s.original))
+	for _, e := range s.original {
+		slice = append(slice, e)
+	}
+	return slice
+}
+
+// String returns a string representation of the set
+func (s *StringSet) String() string {
+	return fmt.Sprintf("%v", s.ToSlice())
+}
+
+// add stores the spelling for the normalized form unless the form is already in the set
+func (s *StringSet) add(n, e string) bool {
+	if s.s.Contains(n) {
+		return false
+	}
+	s.s.Add(n)
+	s.original[n] = e
+	return true
+}
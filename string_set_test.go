@@ -0,0 +1,87 @@
+package set
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/drkennetz/set/settest"
+)
+
+func TestFoldCase(t *testing.T) {
+	for _, tc := range [][2]string{
+		{"Foo@Example.com", "foo@example.com"},
+		{"K", "K"}, // Kelvin sign
+		{"ΣΑΣ", "σας"},
+	} {
+		if FoldCase(tc[0]) != FoldCase(tc[1]) {
+			t.Errorf("Expected %q and %q to fold to the same string, got %q and %q", tc[0], tc[1], FoldCase(tc[0]), FoldCase(tc[1]))
+		}
+	}
+	if FoldCase("İ") != "İ" {
+		t.Errorf("Expected İ to have no other case in simple folding, got %q", FoldCase("İ"))
+	}
+	if FoldCase("a") == FoldCase("b") {
+		t.Error("Expected different letters to stay different")
+	}
+}
+
+func TestNFC(t *testing.T) {
+	if NFC("e\u0301") != "\u00e9" {
+		t.Errorf("Expected the composed é, got %q", NFC("e\u0301"))
+	}
+	s := NewStringSet(NFC, FoldCase)
+	s.Add("Caf\u00e9")
+	if !s.Contains("CAFE\u0301") {
+		t.Error("Expected the decomposed spelling to be in the set")
+	}
+}
+
+func TestStringSet(t *testing.T) {
+	s := NewStringSet(TrimSpace, FoldCase)
+	if !s.Add("Foo@Example.com") {
+		t.Error("StringSet.Add() returned false for a new string")
+	}
+	if s.Add("  foo@example.COM ") {
+		t.Error("StringSet.Add() returned true for an equal string")
+	}
+	if !s.Contains("FOO@EXAMPLE.COM") || s.Len() != 1 {
+		t.Errorf("Expected a single string, got %v", s)
+	}
+	if original, ok := s.Original("foo@example.com"); !ok || original != "Foo@Example.com" {
+		t.Errorf("Expected the first spelling Foo@Example.com, got %q", original)
+	}
+	if _, ok := s.Original("bar@example.com"); ok {
+		t.Error("StringSet.Original() returned true for a missing string")
+	}
+	settest.AssertEqual[string](t, s, NewSetFromSlice([]string{"Foo@Example.com"}))
+	settest.AssertEqual[string](t, s.Normalized(), NewSetFromSlice([]string{"foo@example.com"}))
+	if s.Normalize(" A ") != "a" {
+		t.Errorf("Expected a, got %q", s.Normalize(" A "))
+	}
+	s.Remove("FOO@example.com")
+	if !s.IsEmpty() {
+		t.Errorf("Expected empty set, got %v", s)
+	}
+}
+
+func TestStringSet_Algebra(t *testing.T) {
+	a := NewStringSetFromSlice([]string{"Alpha", "Beta", "gamma"}, FoldCase)
+	b := NewStringSetFromSlice([]string{"beta", "GAMMA", "Delta"}, FoldCase)
+	settest.AssertEqual[string](t, a.Union(b), NewSetFromSlice([]string{"Alpha", "Beta", "gamma", "Delta"}))
+	settest.AssertEqual[string](t, a.Intersection(b), NewSetFromSlice([]string{"Beta", "gamma"}))
+	settest.AssertEqual[string](t, b.Intersection(a), NewSetFromSlice([]string{"beta", "GAMMA"}))
+	settest.AssertEqual[string](t, a.Difference(b), NewSetFromSlice([]string{"Alpha"}))
+	settest.AssertEqual[string](t, a.SymmetricDifference(b), NewSetFromSlice([]string{"Alpha", "Delta"}))
+	settest.AssertEqual[string](t, a.Filter(func(e string) bool { return strings.HasPrefix(e, "B") }), NewSetFromSlice([]string{"Beta"}))
+	if a.IsSubset(b) || a.IsEqual(b) {
+		t.Error("Expected a not to be a subset of b")
+	}
+	c := NewStringSetFromSlice([]string{"ALPHA", "beta", "Gamma"}, FoldCase)
+	if !a.IsEqual(c) || !c.IsSuperset(a) {
+		t.Errorf("Expected %v and %v to be equal", a, c)
+	}
+	c.Clear()
+	if !c.IsEmpty() || c.String() != "[]" {
+		t.Errorf("Expected empty set, got %v", c)
+	}
+}
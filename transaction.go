@@ -0,0 +1,95 @@
+package set
+
+// Tx is a transaction over one or more ThreadSafeSets, see Update.
+// A Tx is only valid inside the function passed to Update.
+type Tx[T comparable] struct {
+	sets map[*ThreadSafeSet[T]]struct{}
+	undo []func()
+	done bool
+}
+
+// Update runs fn in a transaction over sets.
+// Every set is locked for the whole of fn, in a consistent order, so concurrent
+// transactions over overlapping sets can't deadlock and no other goroutine sees
+// the intermediate states between the changes fn makes.
+// If fn returns an error or panics, all of its changes are rolled back before
+// the locks are released. Update returns the error returned by fn.
+// fn must only change the sets through tx; calling methods of the participating
+// sets inside fn deadlocks.
+func Update[T comparable](sets []*ThreadSafeSet[T], fn func(tx *Tx[T]) error) error {
+	unlock := lockAll(sets...)
+	defer unlock()
+	tx := &Tx[T]{
+		sets: make(map[*ThreadSafeSet[T]]struct{}, len(sets)),
+	}
+	for _, s := range sets {
+		tx.sets[s] = struct{}{}
+	}
+	committed := false
+	defer func() {
+		tx.done = true
+		if !committed {
+			tx.rollback()
+		}
+	}()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
+
+// Add adds an element to s
+func (tx *Tx[T]) Add(s *ThreadSafeSet[T], e T) {
+	tx.check(s)
+	if _, ok := s.m[e]; ok {
+		return
+	}
+	s.insert(e)
+	tx.undo = append(tx.undo, func() {
+		s.delete(e)
+	})
+}
+
+// Remove removes an element from s
+func (tx *Tx[T]) Remove(s *ThreadSafeSet[T], e T) {
+	tx.check(s)
+	if _, ok := s.m[e]; !ok {
+		return
+	}
+	s.delete(e)
+	tx.undo = append(tx.undo, func() {
+		s.insert(e)
+	})
+}
+
+// Contains returns true if s contains the element, including changes made earlier in the transaction
+func (tx *Tx[T]) Contains(s *ThreadSafeSet[T], e T) bool {
+	tx.check(s)
+	_, ok := s.m[e]
+	return ok
+}
+
+// Len returns the number of elements in s, including changes made earlier in the transaction
+func (tx *Tx[T]) Len(s *ThreadSafeSet[T]) int {
+	tx.check(s)
+	return len(s.m)
+}
+
+// check panics if the transaction is over or s is not one of its sets
+func (tx *Tx[T]) check(s *ThreadSafeSet[T]) {
+	if tx.done {
+		panic("set: use of a transaction after Update returned")
+	}
+	if _, ok := tx.sets[s]; !ok {
+		panic("set: ThreadSafeSet is not part of the transaction")
+	}
+}
+
+// rollback undoes every change in reverse order
+func (tx *Tx[T]) rollback() {
+	for i := len(tx.undo) - 1; i >= 0; i-- {
+		tx.undo[i]()
+	}
+	tx.undo = nil
+}
@@ -0,0 +1,128 @@
+package set
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/drkennetz/set/settest"
+)
+
+func TestUpdate(t *testing.T) {
+	pending := NewThreadSafeSetFromSlice([]int{1, 2, 3})
+	inFlight := NewThreadSafeSet[int]()
+	err := Update([]*ThreadSafeSet[int]{pending, inFlight}, func(tx *Tx[int]) error {
+		tx.Remove(pending, 2)
+		tx.Add(inFlight, 2)
+		if tx.Contains(pending, 2) || !tx.Contains(inFlight, 2) {
+			t.Error("Tx.Contains() does not see changes made earlier in the transaction")
+		}
+		if tx.Len(pending) != 2 || tx.Len(inFlight) != 1 {
+			t.Error("Tx.Len() does not see changes made earlier in the transaction")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("Update() returned %v", err)
+	}
+	settest.AssertEqual[int](t, pending, NewSetFromSlice([]int{1, 3}))
+	settest.AssertEqual[int](t, inFlight, NewSetFromSlice([]int{2}))
+}
+
+func TestUpdate_RollbackOnError(t *testing.T) {
+	a := NewThreadSafeSetFromSlice([]int{1, 2})
+	b := NewThreadSafeSetFromSlice([]int{3})
+	errAbort := errors.New("abort")
+	err := Update([]*ThreadSafeSet[int]{a, b}, func(tx *Tx[int]) error {
+		tx.Remove(a, 1)
+		tx.Add(b, 1)
+		tx.Add(b, 3)
+		tx.Remove(b, 3)
+		tx.Add(b, 3)
+		tx.Remove(a, 5)
+		tx.Add(a, 4)
+		return errAbort
+	})
+	if err != errAbort {
+		t.Errorf("Update() returned %v, want %v", err, errAbort)
+	}
+	settest.AssertEqual[int](t, a, NewSetFromSlice([]int{1, 2}))
+	settest.AssertEqual[int](t, b, NewSetFromSlice([]int{3}))
+}
+
+func TestUpdate_RollbackOnPanic(t *testing.T) {
+	a := NewThreadSafeSetFromSlice([]int{1})
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("recover() = %v, want boom", r)
+			}
+		}()
+		_ = Update([]*ThreadSafeSet[int]{a}, func(tx *Tx[int]) error {
+			tx.Remove(a, 1)
+			tx.Add(a, 2)
+			panic("boom")
+		})
+	}()
+	settest.AssertEqual[int](t, a, NewSetFromSlice([]int{1}))
+	// the locks must have been released
+	a.Add(3)
+}
+
+func TestUpdate_Misuse(t *testing.T) {
+	a, b := NewThreadSafeSet[int](), NewThreadSafeSet[int]()
+	var leaked *Tx[int]
+	_ = Update([]*ThreadSafeSet[int]{a, a}, func(tx *Tx[int]) error {
+		leaked = tx
+		defer func() {
+			if recover() == nil {
+				t.Error("Tx.Add() on a set outside the transaction did not panic")
+			}
+		}()
+		tx.Add(b, 1)
+		return nil
+	})
+	defer func() {
+		if recover() == nil {
+			t.Error("Tx.Add() after Update returned did not panic")
+		}
+	}()
+	leaked.Add(a, 1)
+}
+
+// go test -race -run TestUpdate_Concurrent .
+func TestUpdate_Concurrent(t *testing.T) {
+	a := NewThreadSafeSet[int]()
+	b := NewThreadSafeSet[int]()
+	for i := 0; i < 100; i++ {
+		a.Add(i)
+	}
+	var wg sync.WaitGroup
+	// movers lock the sets in opposite orders, which must not deadlock
+	for i := 0; i < 100; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			_ = Update([]*ThreadSafeSet[int]{a, b}, func(tx *Tx[int]) error {
+				if tx.Contains(a, i) {
+					tx.Remove(a, i)
+					tx.Add(b, i)
+				}
+				return nil
+			})
+		}(i)
+		go func() {
+			defer wg.Done()
+			_ = Update([]*ThreadSafeSet[int]{b, a}, func(tx *Tx[int]) error {
+				if n := tx.Len(a) + tx.Len(b); n != 100 {
+					t.Errorf("observed %d elements across both sets, want 100", n)
+				}
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+	if a.Len() != 0 || b.Len() != 100 {
+		t.Errorf("got %d and %d elements, want 0 and 100", a.Len(), b.Len())
+	}
+}